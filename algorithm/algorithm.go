@@ -0,0 +1,30 @@
+// Package algorithm implements graph algorithms over the resolved graph
+// model. Nodes and edges are visited in declaration order, so that results
+// are deterministic and follow the order of the DOT source.
+package algorithm
+
+import (
+	"dot-parser/model"
+	"fmt"
+)
+
+type GraphError struct {
+	Message string
+}
+
+func (err *GraphError) Error() string {
+	return fmt.Sprintf("graph error: %s", err.Message)
+}
+
+func makeGraphError(format string, args ...interface{}) *GraphError {
+	return &GraphError{Message: fmt.Sprintf(format, args...)}
+}
+
+// requireDirected returns an error for undirected graphs, on which the
+// algorithm named by name is not defined
+func requireDirected(graph *model.Graph, name string) error {
+	if !graph.IsDirect {
+		return makeGraphError("%s requires a directed graph", name)
+	}
+	return nil
+}
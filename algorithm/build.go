@@ -0,0 +1,147 @@
+package algorithm
+
+import (
+	"dot-parser/builder"
+	"dot-parser/model"
+	"dot-parser/parser"
+)
+
+// newBuilder returns a builder for a graph of the same kind and name as
+// graph, holding its graph attributes
+func newBuilder(graph *model.Graph) *builder.Builder {
+	b := builder.Graph()
+	if graph.IsDirect {
+		b = builder.Digraph()
+	}
+	if graph.IsStrict {
+		b.Strict()
+	}
+	if graph.Name.IsSome() {
+		b.Name(graph.Name.Unwrap())
+	}
+
+	for _, key := range graph.Attributes.Keys() {
+		b.Attr(key, graph.Attributes[key])
+	}
+	return b
+}
+
+// addNode declares a node with its resolved attributes
+func addNode(b *builder.Builder, node *model.Node, extra ...parser.AttributeMap) {
+	attributes := merged(node.Attributes, extra)
+	if len(attributes) > 0 {
+		b.Node(node.Name, attributes)
+	} else {
+		b.Node(node.Name)
+	}
+}
+
+// addEdge declares an edge with its ports and resolved attributes
+func addEdge(b *builder.Builder, edge *model.Edge, extra ...parser.AttributeMap) {
+	tail, head := builder.ID(edge.Tail.Name), builder.ID(edge.Head.Name)
+	if edge.TailPort.IsSome() {
+		tail = builder.Port(edge.Tail.Name, edge.TailPort.Unwrap())
+	}
+	if edge.HeadPort.IsSome() {
+		head = builder.Port(edge.Head.Name, edge.HeadPort.Unwrap())
+	}
+
+	attributes := merged(edge.Attributes, extra)
+	if len(attributes) > 0 {
+		b.EdgeID(tail, head, attributes)
+	} else {
+		b.EdgeID(tail, head)
+	}
+}
+
+// merged returns a copy of attributes overridden by the extra attributes
+func merged(attributes parser.AttributeMap, extra []parser.AttributeMap) parser.AttributeMap {
+	copied := make(parser.AttributeMap, len(attributes))
+	for key, value := range attributes {
+		copied[key] = value
+	}
+	for _, attributes := range extra {
+		for key, value := range attributes {
+			copied[key] = value
+		}
+	}
+	return copied
+}
+
+// statementFilter copies statement lists keeping every attribute statement
+// and the node and edge statements of the kept nodes and edges. A dropped
+// edge statement is replaced by node statements for its kept endpoints it
+// was the first mention of, in the graph or in its subgraph, so that nodes
+// keep their attributes, their order and their subgraphs. Subgraphs left
+// without nodes are dropped.
+type statementFilter struct {
+	keepNode func(name string) bool
+	keepEdge func(stmt *parser.Edge) bool
+	// declared holds the nodes mentioned so far in the graph
+	declared map[string]bool
+}
+
+func makeStatementFilter(keepNode func(string) bool, keepEdge func(*parser.Edge) bool) *statementFilter {
+	return &statementFilter{keepNode: keepNode, keepEdge: keepEdge, declared: make(map[string]bool)}
+}
+
+// filter filters a statement list; local, nil at the top level, holds the
+// nodes mentioned so far in the current subgraph
+func (f *statementFilter) filter(stmts []parser.Statement, local map[string]bool) []parser.Statement {
+	mention := func(name string) bool {
+		isNew := !f.declared[name] || (local != nil && !local[name])
+		f.declared[name] = true
+		if local != nil {
+			local[name] = true
+		}
+		return isNew
+	}
+
+	var filtered []parser.Statement
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *parser.Node:
+			if !f.keepNode(stmt.ID.Name) {
+				continue
+			}
+			mention(stmt.ID.Name)
+		case *parser.Edge:
+			if !f.keepEdge(stmt) || !f.keepNode(stmt.Lnode.Name) || !f.keepNode(stmt.Rnode.Name) {
+				for _, id := range []parser.NodeID{stmt.Lnode, stmt.Rnode} {
+					if f.keepNode(id.Name) && mention(id.Name) {
+						filtered = append(filtered, &parser.Node{ID: builder.ID(id.Name), Position: stmt.Position})
+					}
+				}
+				continue
+			}
+			mention(stmt.Lnode.Name)
+			mention(stmt.Rnode.Name)
+		case *parser.Subgraph:
+			subgraph := *stmt
+			subgraph.Statements = f.filter(stmt.Statements, make(map[string]bool))
+			if mentionsNodes(stmt.Statements) && !mentionsNodes(subgraph.Statements) {
+				continue
+			}
+			filtered = append(filtered, &subgraph)
+			continue
+		}
+		filtered = append(filtered, stmt)
+	}
+	return filtered
+}
+
+// mentionsNodes reports whether a statement list holds node or edge
+// statements, in it or in its subgraphs
+func mentionsNodes(stmts []parser.Statement) bool {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *parser.Node, *parser.Edge:
+			return true
+		case *parser.Subgraph:
+			if mentionsNodes(stmt.Statements) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package algorithm
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/builder"
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"math"
+)
+
+type CostOptions struct {
+	// Node reads the duration of every node
+	Node WeightOptions
+	// Edge reads the cost of every edge, e.g. a delay between two tasks
+	Edge WeightOptions
+}
+
+// DefaultCostOptions reads durations from the duration attribute of the
+// nodes, 0 when missing, and gives no cost to edges
+func DefaultCostOptions() CostOptions {
+	return CostOptions{Node: WeightOptions{Attribute: "duration"}}
+}
+
+// Task holds the timing of a node in a Schedule
+type Task struct {
+	Node     *model.Node
+	Duration float64
+	// EarliestStart is the time the node can start at once all of its
+	// predecessors are done, and LatestStart the time it must start at not
+	// to delay the end of the schedule
+	EarliestStart  float64
+	EarliestFinish float64
+	LatestStart    float64
+	LatestFinish   float64
+	// Slack is how much the node can be delayed without delaying the end
+	// of the schedule
+	Slack float64
+}
+
+// Schedule is the result of the critical path method
+type Schedule struct {
+	// Tasks holds the timing of every node, in declaration order
+	Tasks []Task
+	// CriticalPath is a longest path, its cost is the length of the
+	// schedule: the durations of its nodes plus the costs of its edges
+	CriticalPath Path
+}
+
+// Length returns the time the last task finishes at
+func (schedule *Schedule) Length() float64 {
+	return schedule.CriticalPath.Cost
+}
+
+// CriticalPath schedules the nodes of a DAG as tasks that start as soon as
+// the tasks before them, plus the cost of the edges from them, are done,
+// with the durations and costs read as options tell; nodes without
+// predecessors start at 0. Among longest paths of the same cost, the one
+// ending at the first declared node, and going through the first declared
+// edges, is returned. Graphs with a cycle give a CycleError, undirected
+// graphs a GraphError.
+func CriticalPath(graph *model.Graph, options CostOptions) Result[*Schedule] {
+	order := TopologicalSort(graph)
+	if order.IsErr() {
+		return Err[*Schedule](order.UnwrapErr())
+	}
+	weights, err := options.Edge.weights(graph)
+	if err != nil {
+		return Err[*Schedule](err)
+	}
+	tasks := make([]Task, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		duration, err := options.Node.read(node.Attributes, node.Position)
+		if err != nil {
+			return Err[*Schedule](err)
+		}
+		tasks[i] = Task{Node: node, Duration: duration}
+	}
+
+	// Forward pass: parent holds the edge setting the earliest start
+	parent := make([]*model.Edge, len(graph.Nodes))
+	var last *Task
+	for _, node := range order.Unwrap() {
+		task := &tasks[node.Index]
+		for _, edge := range graph.In(node) {
+			start := tasks[edge.Tail.Index].EarliestFinish + weights[edge.Index]
+			if parent[node.Index] == nil || start > task.EarliestStart {
+				task.EarliestStart = start
+				parent[node.Index] = edge
+			}
+		}
+		task.EarliestFinish = task.EarliestStart + task.Duration
+		if last == nil || task.EarliestFinish > last.EarliestFinish ||
+			(task.EarliestFinish == last.EarliestFinish && node.Index < last.Node.Index) {
+			last = task
+		}
+	}
+
+	schedule := &Schedule{Tasks: tasks}
+	if last == nil {
+		return Ok(schedule)
+	}
+	length := last.EarliestFinish
+
+	// Backward pass
+	nodes := order.Unwrap()
+	for i := len(nodes) - 1; i >= 0; i-- {
+		task := &tasks[nodes[i].Index]
+		task.LatestFinish = length
+		for _, edge := range graph.Out(nodes[i]) {
+			task.LatestFinish = math.Min(task.LatestFinish, tasks[edge.Head.Index].LatestStart-weights[edge.Index])
+		}
+		task.LatestStart = task.LatestFinish - task.Duration
+		task.Slack = task.LatestStart - task.EarliestStart
+	}
+
+	path := Path{Nodes: []*model.Node{last.Node}, Cost: length}
+	for node := last.Node; parent[node.Index] != nil; {
+		edge := parent[node.Index]
+		node = edge.Tail
+		path.Nodes = append([]*model.Node{node}, path.Nodes...)
+		path.Edges = append([]*model.Edge{edge}, path.Edges...)
+	}
+	schedule.CriticalPath = path
+	return Ok(schedule)
+}
+
+// AnnotateSchedule returns graph with the timing of every node, as the
+// earliest_start, latest_start and slack attributes and an xlabel showing
+// "<earliest start> / <latest start>", in node statements at the end of
+// the graph. The nodes and edges of the critical path also get the
+// highlight attributes, as Highlight adds them.
+func AnnotateSchedule(graph parser.Graph, schedule *Schedule, highlight parser.AttributeMap) parser.Graph {
+	marked := make(map[*parser.Edge]bool)
+	for _, edge := range schedule.CriticalPath.Edges {
+		for _, stmt := range edge.Statements {
+			marked[stmt] = true
+		}
+	}
+	critical := make(map[*model.Node]bool)
+	for _, node := range schedule.CriticalPath.Nodes {
+		critical[node] = true
+	}
+
+	annotated := graph
+	annotated.Statements = withEdgeAttributes(graph.Statements, marked, highlight)
+	for _, task := range schedule.Tasks {
+		attributes := parser.AttributeMap{
+			"earliest_start": attribute.FormatDouble(task.EarliestStart),
+			"latest_start":   attribute.FormatDouble(task.LatestStart),
+			"slack":          attribute.FormatDouble(task.Slack),
+			"xlabel":         attribute.FormatDouble(task.EarliestStart) + " / " + attribute.FormatDouble(task.LatestStart),
+		}
+		if critical[task.Node] {
+			attributes = merged(attributes, []parser.AttributeMap{highlight})
+		}
+		annotated.Statements = append(annotated.Statements, &parser.Node{ID: builder.ID(task.Node.Name), Attributes: []parser.AttributeMap{attributes}})
+	}
+	return annotated
+}
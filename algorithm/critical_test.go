@@ -0,0 +1,99 @@
+package algorithm
+
+import (
+	"dot-parser/internal/testutil"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"dot-parser/printer"
+	"reflect"
+	"testing"
+)
+
+const pipeline = `digraph pipeline {
+	fetch [duration=2]
+	build [duration=5]
+	lint [duration=1]
+	test [duration=3]
+	deploy [duration=1]
+	fetch -> build -> test -> deploy
+	fetch -> lint -> deploy
+	docs [duration=2]
+}`
+
+func TestCriticalPath(t *testing.T) {
+	schedule := CriticalPath(resolveGraph(t, pipeline), DefaultCostOptions()).Unwrap()
+	if got := names(schedule.CriticalPath.Nodes); !reflect.DeepEqual(got, []string{"fetch", "build", "test", "deploy"}) {
+		t.Fatalf("Expected the critical path through build and test, got %v", got)
+	}
+	if len(schedule.CriticalPath.Edges) != 3 || schedule.Length() != 11 {
+		t.Fatalf("Expected 3 edges and a length of 11, got %d and %v", len(schedule.CriticalPath.Edges), schedule.Length())
+	}
+
+	expected := map[string][3]float64{
+		"fetch":  {0, 0, 0},
+		"build":  {2, 2, 0},
+		"lint":   {2, 9, 7},
+		"test":   {7, 7, 0},
+		"deploy": {10, 10, 0},
+		"docs":   {0, 9, 9},
+	}
+	for _, task := range schedule.Tasks {
+		got := [3]float64{task.EarliestStart, task.LatestStart, task.Slack}
+		if got != expected[task.Node.Name] {
+			t.Fatalf("Expected %s to start in %v, got %v", task.Node.Name, expected[task.Node.Name], got)
+		}
+		if task.EarliestFinish != task.EarliestStart+task.Duration || task.LatestFinish != task.LatestStart+task.Duration {
+			t.Fatalf("Expected the finish of %s to follow its start", task.Node.Name)
+		}
+	}
+}
+
+func TestCriticalPathEdgeCosts(t *testing.T) {
+	graph := resolveGraph(t, `digraph {
+		a -> b [delay=4]
+		a -> c [delay=1]
+		c -> b [delay=2]
+		b -> d
+		d [duration=1]
+	}`)
+	options := DefaultCostOptions()
+	options.Edge = WeightOptions{Attribute: "delay"}
+	schedule := CriticalPath(graph, options).Unwrap()
+	if got := names(schedule.CriticalPath.Nodes); !reflect.DeepEqual(got, []string{"a", "b", "d"}) || schedule.Length() != 5 {
+		t.Fatalf("Expected a, b, d of length 5, got %v of length %v", got, schedule.Length())
+	}
+
+	if CriticalPath(resolveGraph(t, `digraph { a -> b -> a }`), DefaultCostOptions()).IsOk() {
+		t.Fatal("Expected a CycleError")
+	}
+	if CriticalPath(resolveGraph(t, `digraph { a [duration=long] }`), DefaultCostOptions()).IsOk() {
+		t.Fatal("Expected an AttributeError")
+	}
+	if empty := CriticalPath(resolveGraph(t, `digraph {}`), DefaultCostOptions()).Unwrap(); empty.Length() != 0 {
+		t.Fatalf("Expected an empty schedule, got a length of %v", empty.Length())
+	}
+}
+
+func TestAnnotateSchedule(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+	a [duration=1]
+	b [duration=2]
+	a -> b
+	a -> c
+}`)
+	schedule := CriticalPath(model.Resolve(graph), DefaultCostOptions()).Unwrap()
+
+	expected := `digraph {
+	a [duration=1]
+	b [duration=2]
+	a -> b [color=red]
+	a -> c
+	a [color=red, earliest_start=0, latest_start=0, slack=0, xlabel="0 / 0"]
+	b [color=red, earliest_start=1, latest_start=1, slack=0, xlabel="1 / 1"]
+	c [earliest_start=1, latest_start=3, slack=2, xlabel="1 / 3"]
+}
+`
+	if printed := printer.Print(AnnotateSchedule(graph, schedule, parser.AttributeMap{"color": "red"})).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+}
@@ -0,0 +1,254 @@
+package algorithm
+
+import (
+	"dot-parser/model"
+	"dot-parser/option"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"sort"
+)
+
+// DominatorTree holds the dominators of the nodes of a digraph reachable
+// from a root node: a node dominates another when every path from the root
+// to the other goes through it. For post-dominator trees paths go from the
+// other node to the root, which is then the exit of the graph.
+type DominatorTree struct {
+	Root *model.Node
+	// IsPost tells whether the tree holds post-dominators
+	IsPost bool
+
+	graph *model.Graph
+	// idom holds the immediate dominator of each node, the root for the
+	// root and nil for the nodes that are not reachable
+	idom      []*model.Node
+	frontiers [][]*model.Node
+}
+
+// Dominators computes the dominator tree of a digraph from its entry node,
+// with the algorithm of Cooper, Harvey and Kennedy. Nodes that cannot be
+// reached from entry are not in the tree. Unknown entries and undirected
+// graphs give a GraphError.
+func Dominators(graph *model.Graph, entry string) Result[*DominatorTree] {
+	return dominators(graph, entry, false)
+}
+
+// PostDominators computes the post-dominator tree of a digraph towards its
+// exit node, the dominator tree of the graph with its edges reversed.
+// Nodes from which exit cannot be reached are not in the tree.
+func PostDominators(graph *model.Graph, exit string) Result[*DominatorTree] {
+	return dominators(graph, exit, true)
+}
+
+func dominators(graph *model.Graph, root string, isPost bool) Result[*DominatorTree] {
+	if err := requireDirected(graph, "dominator tree"); err != nil {
+		return Err[*DominatorTree](err)
+	}
+	start, err := lookupNode(graph, root)
+	if err != nil {
+		return Err[*DominatorTree](err)
+	}
+
+	tree := &DominatorTree{Root: start, IsPost: isPost, graph: graph, idom: make([]*model.Node, len(graph.Nodes))}
+	order := tree.postorder()
+	number := make([]int, len(graph.Nodes))
+	for i, node := range order {
+		number[node.Index] = i
+	}
+
+	intersect := func(first *model.Node, second *model.Node) *model.Node {
+		for first != second {
+			for number[first.Index] < number[second.Index] {
+				first = tree.idom[first.Index]
+			}
+			for number[second.Index] < number[first.Index] {
+				second = tree.idom[second.Index]
+			}
+		}
+		return first
+	}
+
+	tree.idom[start.Index] = start
+	for changed := true; changed; {
+		changed = false
+		// Reverse postorder, skipping the root that comes last in postorder
+		for i := len(order) - 2; i >= 0; i-- {
+			node := order[i]
+			var idom *model.Node
+			for _, predecessor := range tree.predecessors(node) {
+				if tree.idom[predecessor.Index] == nil {
+					continue
+				}
+				if idom == nil {
+					idom = predecessor
+				} else {
+					idom = intersect(predecessor, idom)
+				}
+			}
+			if tree.idom[node.Index] != idom {
+				tree.idom[node.Index] = idom
+				changed = true
+			}
+		}
+	}
+
+	tree.frontiers = tree.computeFrontiers()
+	return Ok(tree)
+}
+
+// predecessors returns the nodes preceding a node, in the graph or in the
+// reversed graph for post-dominators
+func (tree *DominatorTree) predecessors(node *model.Node) []*model.Node {
+	return neighbours(tree.graph, node, !tree.IsPost)
+}
+
+// neighbours returns the heads of the edges leaving a node, or the tails of
+// the ones entering it when reversed
+func neighbours(graph *model.Graph, node *model.Node, reversed bool) []*model.Node {
+	edges := graph.Out(node)
+	if reversed {
+		edges = graph.In(node)
+	}
+	nodes := make([]*model.Node, len(edges))
+	for i, edge := range edges {
+		nodes[i] = edge.Opposite(node)
+	}
+	return nodes
+}
+
+// postorder returns the nodes reachable from the root in the postorder of
+// an iterative depth-first search following the edges in declaration order
+func (tree *DominatorTree) postorder() []*model.Node {
+	visited := make([]bool, len(tree.graph.Nodes))
+	var order []*model.Node
+	push := func(node *model.Node) *frame {
+		visited[node.Index] = true
+		edges := tree.graph.Out(node)
+		if tree.IsPost {
+			edges = tree.graph.In(node)
+		}
+		return &frame{node: node, edges: edges}
+	}
+	frames := []*frame{push(tree.Root)}
+	for len(frames) > 0 {
+		top := frames[len(frames)-1]
+		if top.next < len(top.edges) {
+			next := top.edges[top.next].Opposite(top.node)
+			top.next++
+			if !visited[next.Index] {
+				frames = append(frames, push(next))
+			}
+			continue
+		}
+		frames = frames[:len(frames)-1]
+		order = append(order, top.node)
+	}
+	return order
+}
+
+// computeFrontiers computes the dominance frontiers: the frontier of a node
+// holds the nodes it does not strictly dominate but dominates one of the
+// predecessors of. The root has a virtual predecessor, the start of every
+// path, so that it is strictly dominated by no node and the runners going
+// up from its predecessors reach it.
+func (tree *DominatorTree) computeFrontiers() [][]*model.Node {
+	frontiers := make([][]*model.Node, len(tree.graph.Nodes))
+	added := make(map[[2]int]bool)
+	for _, node := range tree.graph.Nodes {
+		if tree.idom[node.Index] == nil {
+			continue
+		}
+		for _, predecessor := range tree.predecessors(node) {
+			if tree.idom[predecessor.Index] == nil {
+				continue
+			}
+			for runner := predecessor; node == tree.Root || runner != tree.idom[node.Index]; runner = tree.idom[runner.Index] {
+				if pair := [2]int{runner.Index, node.Index}; !added[pair] {
+					added[pair] = true
+					frontiers[runner.Index] = append(frontiers[runner.Index], node)
+				}
+				if runner == tree.Root {
+					break
+				}
+			}
+		}
+	}
+
+	for _, frontier := range frontiers {
+		sort.Slice(frontier, func(i, j int) bool { return frontier[i].Index < frontier[j].Index })
+	}
+	return frontiers
+}
+
+// Contains reports whether a node is in the tree, that is whether it is
+// reachable from the root, or the root from it for post-dominators
+func (tree *DominatorTree) Contains(name string) bool {
+	node := tree.graph.Node(name)
+	return node.IsSome() && tree.idom[node.Unwrap().Index] != nil
+}
+
+// ImmediateDominator returns the closest strict dominator of a node, none
+// for the root and the nodes not in the tree
+func (tree *DominatorTree) ImmediateDominator(name string) option.Option[*model.Node] {
+	if !tree.Contains(name) || name == tree.Root.Name {
+		return option.None[*model.Node]()
+	}
+	return option.Some(tree.idom[tree.graph.Node(name).Unwrap().Index])
+}
+
+// Dominates reports whether a node dominates another one, every node of
+// the tree dominating itself
+func (tree *DominatorTree) Dominates(dominator string, name string) bool {
+	if !tree.Contains(dominator) || !tree.Contains(name) {
+		return false
+	}
+	for node := tree.graph.Node(name).Unwrap(); ; node = tree.idom[node.Index] {
+		if node.Name == dominator {
+			return true
+		}
+		if node == tree.Root {
+			return false
+		}
+	}
+}
+
+// Children returns the nodes a node is the immediate dominator of, in
+// declaration order
+func (tree *DominatorTree) Children(name string) []*model.Node {
+	var children []*model.Node
+	for _, node := range tree.graph.Nodes {
+		if node != tree.Root && tree.idom[node.Index] != nil && tree.idom[node.Index].Name == name {
+			children = append(children, node)
+		}
+	}
+	return children
+}
+
+// Frontier returns the dominance frontier of a node, in declaration order:
+// the nodes where its dominance ends, that it does not strictly dominate
+// but dominates one of the predecessors of. For post-dominator trees
+// predecessors are successors in the graph.
+func (tree *DominatorTree) Frontier(name string) []*model.Node {
+	if !tree.Contains(name) {
+		return nil
+	}
+	return tree.frontiers[tree.graph.Node(name).Unwrap().Index]
+}
+
+// Graph returns the tree as a digraph named and with the graph attributes
+// of the original one: the nodes of the tree, with their resolved
+// attributes, in declaration order, and an edge from the immediate
+// dominator of each node to it
+func (tree *DominatorTree) Graph() parser.Graph {
+	b := newBuilder(tree.graph)
+	for _, node := range tree.graph.Nodes {
+		if tree.idom[node.Index] != nil {
+			addNode(b, node)
+		}
+	}
+	for _, node := range tree.graph.Nodes {
+		if idom := tree.idom[node.Index]; idom != nil && node != tree.Root {
+			b.Edge(idom.Name, node.Name)
+		}
+	}
+	return b.Build()
+}
@@ -0,0 +1,105 @@
+package algorithm
+
+import (
+	"dot-parser/printer"
+	"reflect"
+	"testing"
+)
+
+const cfg = `digraph cfg {
+	entry -> a
+	a -> b
+	a -> c
+	b -> d
+	c -> d
+	d -> a
+	d -> exit
+	dead -> exit
+}`
+
+func TestDominators(t *testing.T) {
+	tree := Dominators(resolveGraph(t, cfg), "entry").Unwrap()
+
+	expected := map[string]string{"a": "entry", "b": "a", "c": "a", "d": "a", "exit": "d"}
+	for name, idom := range expected {
+		if got := tree.ImmediateDominator(name); got.IsNone() || got.Unwrap().Name != idom {
+			t.Fatalf("Expected %s to be the immediate dominator of %s", idom, name)
+		}
+	}
+	if tree.ImmediateDominator("entry").IsSome() || tree.Contains("dead") || tree.ImmediateDominator("dead").IsSome() {
+		t.Fatal("Expected entry and dead to have no immediate dominator")
+	}
+	if !tree.Dominates("a", "exit") || !tree.Dominates("d", "d") || tree.Dominates("b", "d") || tree.Dominates("dead", "exit") {
+		t.Fatal("Expected a to dominate exit, d itself, and b and dead not to dominate d and exit")
+	}
+	if got := names(tree.Children("a")); !reflect.DeepEqual(got, []string{"b", "c", "d"}) {
+		t.Fatalf("Expected b, c and d to be the children of a, got %v", got)
+	}
+
+	frontiers := map[string][]string{"entry": {}, "a": {"a"}, "b": {"d"}, "c": {"d"}, "d": {"a"}, "exit": {}}
+	for name, frontier := range frontiers {
+		if got := names(tree.Frontier(name)); !reflect.DeepEqual(got, frontier) {
+			t.Fatalf("Expected the frontier of %s to be %v, got %v", name, frontier, got)
+		}
+	}
+
+	expectedGraph := `digraph cfg {
+	entry
+	a
+	b
+	c
+	d
+	exit
+	entry -> a
+	a -> b
+	a -> c
+	a -> d
+	d -> exit
+}
+`
+	if printed := printer.Print(tree.Graph()).Unwrap(); printed != expectedGraph {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expectedGraph, printed)
+	}
+}
+
+func TestPostDominators(t *testing.T) {
+	tree := PostDominators(resolveGraph(t, cfg), "exit").Unwrap()
+
+	expected := map[string]string{"entry": "a", "a": "d", "b": "d", "c": "d", "d": "exit", "dead": "exit"}
+	for name, idom := range expected {
+		if got := tree.ImmediateDominator(name); got.IsNone() || got.Unwrap().Name != idom {
+			t.Fatalf("Expected %s to be the immediate post-dominator of %s", idom, name)
+		}
+	}
+	if got := names(tree.Frontier("b")); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("Expected the post-dominance frontier of b to be a, got %v", got)
+	}
+	if got := names(tree.Frontier("d")); !reflect.DeepEqual(got, []string{"d"}) {
+		t.Fatalf("Expected the post-dominance frontier of d to be d, got %v", got)
+	}
+
+	if Dominators(resolveGraph(t, `graph { a -- b }`), "a").IsOk() {
+		t.Fatal("Expected a GraphError for an undirected graph")
+	}
+	if Dominators(resolveGraph(t, cfg), "nowhere").IsOk() {
+		t.Fatal("Expected a GraphError for an unknown entry")
+	}
+}
+
+func TestDominanceFrontiersOfTheEntry(t *testing.T) {
+	tree := Dominators(resolveGraph(t, `digraph { a -> b; a -> c; b -> d; c -> d; d -> a }`), "a").Unwrap()
+	frontiers := map[string][]string{"a": {"a"}, "b": {"d"}, "c": {"d"}, "d": {"a"}}
+	for name, frontier := range frontiers {
+		if got := names(tree.Frontier(name)); !reflect.DeepEqual(got, frontier) {
+			t.Fatalf("Expected the frontier of %s to be %v, got %v", name, frontier, got)
+		}
+	}
+
+	tree = Dominators(resolveGraph(t, `digraph { a -> b; b -> a }`), "a").Unwrap()
+	if got := names(tree.Frontier("b")); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("Expected the frontier of b to be a, got %v", got)
+	}
+	if got := names(tree.Frontier("a")); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("Expected the frontier of a to be a, got %v", got)
+	}
+}
@@ -0,0 +1,303 @@
+package algorithm
+
+import (
+	"dot-parser/model"
+	"dot-parser/option"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"strings"
+)
+
+type MatchOptions struct {
+	// Node, when not nil, tells whether a pattern node can be matched to a
+	// target node
+	Node func(pattern *model.Node, target *model.Node) bool
+	// Edge, when not nil, tells whether a pattern edge can be matched to a
+	// target edge
+	Edge func(pattern *model.Edge, target *model.Edge) bool
+	// Induced requires the target nodes of a match to have no edges between
+	// them but the ones matched to pattern edges
+	Induced bool
+	// Limit is the most matches returned, 0 for no limit
+	Limit int
+}
+
+// AttributeMatchOptions matches pattern nodes and edges to the target ones
+// with the same values for all of their attributes, see MatchAttributes
+func AttributeMatchOptions() MatchOptions {
+	return MatchOptions{
+		Node: func(pattern *model.Node, target *model.Node) bool {
+			return MatchAttributes(pattern.Attributes, target.Attributes)
+		},
+		Edge: func(pattern *model.Edge, target *model.Edge) bool {
+			return MatchAttributes(pattern.Attributes, target.Attributes)
+		},
+	}
+}
+
+// MatchAttributes reports whether target has every attribute of pattern with
+// the same value, a pattern value of "*" matching any value
+func MatchAttributes(pattern parser.AttributeMap, target parser.AttributeMap) bool {
+	for key, value := range pattern {
+		if targetValue, exists := target[key]; !exists || (value != "*" && value != targetValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParsePattern parses a pattern written in DOT, whose node and edge
+// attributes, defaults included, can be matched by AttributeMatchOptions
+func ParsePattern(source string) Result[*model.Graph] {
+	return Map(parser.ParseFile(strings.NewReader(source)), func(graph parser.Graph) *model.Graph {
+		return model.Resolve(graph)
+	})
+}
+
+// Match maps the nodes and the edges of a pattern to the ones of a target
+// graph
+type Match struct {
+	// Nodes holds the target node matched to each pattern node, by name
+	Nodes map[string]*model.Node
+	// Edges holds the target edge matched to each pattern edge, by index
+	Edges []*model.Edge
+}
+
+// Isomorphism returns a one to one mapping between the nodes and the edges
+// of two graphs of the same kind, preserving the edges as well as the node
+// and edge compatibility of options, none when there is not any
+func Isomorphism(first *model.Graph, second *model.Graph, options MatchOptions) option.Option[Match] {
+	if len(first.Nodes) != len(second.Nodes) || len(first.Edges) != len(second.Edges) {
+		return option.None[Match]()
+	}
+	options.Induced, options.Limit = true, 1
+	matches := newMatcher(first, second, options, true).run()
+	if len(matches) == 0 {
+		return option.None[Match]()
+	}
+	return option.Some(matches[0])
+}
+
+// SubgraphIsomorphisms returns the matches of a pattern in a target graph
+// of the same kind: mappings of the pattern nodes to distinct target nodes,
+// and of the pattern edges to distinct target edges joining the matched
+// nodes the same way, with the node and edge compatibility of options.
+// Matches are found by a VF2 style search, that extends a partial match
+// one pattern node at a time, choosing the candidates among the neighbours
+// of the target nodes matched so far; symmetric patterns match the same
+// target nodes once for each of their automorphisms.
+func SubgraphIsomorphisms(pattern *model.Graph, target *model.Graph, options MatchOptions) []Match {
+	return newMatcher(pattern, target, options, false).run()
+}
+
+type matcher struct {
+	pattern *model.Graph
+	target  *model.Graph
+	options MatchOptions
+	// isExact requires matched nodes to have the same degrees
+	isExact      bool
+	patternEdges map[[2]int][]*model.Edge
+	targetEdges  map[[2]int][]*model.Edge
+
+	// order is the order pattern nodes are matched in, anchor holds the
+	// node ordered before each one it is adjacent to, if any
+	order   []*model.Node
+	anchor  []*model.Node
+	core    []*model.Node
+	inverse []*model.Node
+	matches []Match
+}
+
+func newMatcher(pattern *model.Graph, target *model.Graph, options MatchOptions, isExact bool) *matcher {
+	m := &matcher{
+		pattern:      pattern,
+		target:       target,
+		options:      options,
+		isExact:      isExact,
+		patternEdges: edgesByEndpoints(pattern),
+		targetEdges:  edgesByEndpoints(target),
+		anchor:       make([]*model.Node, len(pattern.Nodes)),
+		core:         make([]*model.Node, len(pattern.Nodes)),
+		inverse:      make([]*model.Node, len(target.Nodes)),
+	}
+	m.orderNodes()
+	return m
+}
+
+func (m *matcher) run() []Match {
+	if m.pattern.IsDirect == m.target.IsDirect {
+		m.search(0)
+	}
+	return m.matches
+}
+
+// edgesByEndpoints groups the edges by tail and head, in declaration order;
+// endpoints of undirected edges are ordered by index
+func edgesByEndpoints(graph *model.Graph) map[[2]int][]*model.Edge {
+	edges := make(map[[2]int][]*model.Edge)
+	for _, edge := range graph.Edges {
+		key := endpoints(graph, edge.Tail, edge.Head)
+		edges[key] = append(edges[key], edge)
+	}
+	return edges
+}
+
+func endpoints(graph *model.Graph, tail *model.Node, head *model.Node) [2]int {
+	if !graph.IsDirect && tail.Index > head.Index {
+		return [2]int{head.Index, tail.Index}
+	}
+	return [2]int{tail.Index, head.Index}
+}
+
+// adjacent returns the nodes joined to a node by an edge either way, a node
+// may be listed more than once
+func adjacent(graph *model.Graph, node *model.Node) []*model.Node {
+	return append(neighbours(graph, node, false), neighbours(graph, node, true)...)
+}
+
+// orderNodes orders the pattern nodes so that each one is adjacent to as
+// many nodes before it as possible, then has the highest degree, so that
+// the candidates are few and mismatches are found early
+func (m *matcher) orderNodes() {
+	ordered := make([]bool, len(m.pattern.Nodes))
+	links := make([]int, len(m.pattern.Nodes))
+	for len(m.order) < len(m.pattern.Nodes) {
+		var next *model.Node
+		for _, node := range m.pattern.Nodes {
+			if ordered[node.Index] {
+				continue
+			}
+			if next == nil || links[node.Index] > links[next.Index] ||
+				(links[node.Index] == links[next.Index] && len(adjacent(m.pattern, node)) > len(adjacent(m.pattern, next))) {
+				next = node
+			}
+		}
+
+		ordered[next.Index] = true
+		m.order = append(m.order, next)
+		for _, other := range adjacent(m.pattern, next) {
+			if !ordered[other.Index] {
+				links[other.Index]++
+				if m.anchor[other.Index] == nil {
+					m.anchor[other.Index] = next
+				}
+			}
+		}
+	}
+}
+
+func (m *matcher) search(depth int) {
+	if m.options.Limit > 0 && len(m.matches) >= m.options.Limit {
+		return
+	}
+	if depth == len(m.order) {
+		m.matches = append(m.matches, m.match())
+		return
+	}
+
+	node := m.order[depth]
+	candidates := m.target.Nodes
+	if anchor := m.anchor[node.Index]; anchor != nil {
+		candidates = adjacent(m.target, m.core[anchor.Index])
+	}
+	tried := make(map[*model.Node]bool)
+	for _, candidate := range candidates {
+		if tried[candidate] {
+			continue
+		}
+		tried[candidate] = true
+		if !m.isFeasible(node, candidate) {
+			continue
+		}
+
+		m.core[node.Index], m.inverse[candidate.Index] = candidate, node
+		m.search(depth + 1)
+		m.core[node.Index], m.inverse[candidate.Index] = nil, nil
+	}
+}
+
+// isFeasible tells whether a pattern node can be matched to a target node,
+// given the nodes matched so far
+func (m *matcher) isFeasible(node *model.Node, candidate *model.Node) bool {
+	if m.inverse[candidate.Index] != nil || (m.options.Node != nil && !m.options.Node(node, candidate)) {
+		return false
+	}
+
+	patternOut, patternIn := degrees(m.pattern, node)
+	targetOut, targetIn := degrees(m.target, candidate)
+	if patternOut > targetOut || patternIn > targetIn || (m.isExact && (patternOut != targetOut || patternIn != targetIn)) {
+		return false
+	}
+
+	m.core[node.Index] = candidate
+	defer func() { m.core[node.Index] = nil }()
+	for _, other := range m.pattern.Nodes {
+		if m.core[other.Index] == nil {
+			continue
+		}
+		if _, ok := m.matchEdges(node, other); !ok {
+			return false
+		}
+		if m.pattern.IsDirect {
+			if _, ok := m.matchEdges(other, node); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// degrees returns the out and in degrees of a node of a digraph, the
+// degree and 0 in undirected graphs
+func degrees(graph *model.Graph, node *model.Node) (int, int) {
+	if !graph.IsDirect {
+		return len(graph.Out(node)) + len(graph.In(node)), 0
+	}
+	return len(graph.Out(node)), len(graph.In(node))
+}
+
+// matchEdges matches the pattern edges from tail to head to distinct target
+// edges between the nodes matched to them
+func (m *matcher) matchEdges(tail *model.Node, head *model.Node) ([]*model.Edge, bool) {
+	patternEdges := m.patternEdges[endpoints(m.pattern, tail, head)]
+	targetEdges := m.targetEdges[endpoints(m.target, m.core[tail.Index], m.core[head.Index])]
+	if len(patternEdges) > len(targetEdges) || (m.options.Induced && len(patternEdges) != len(targetEdges)) {
+		return nil, false
+	}
+
+	matched := make([]*model.Edge, len(patternEdges))
+	used := make([]bool, len(targetEdges))
+	var assign func(i int) bool
+	assign = func(i int) bool {
+		if i == len(patternEdges) {
+			return true
+		}
+		for j, edge := range targetEdges {
+			if used[j] || (m.options.Edge != nil && !m.options.Edge(patternEdges[i], edge)) {
+				continue
+			}
+			used[j], matched[i] = true, edge
+			if assign(i + 1) {
+				return true
+			}
+			used[j] = false
+		}
+		return false
+	}
+	return matched, assign(0)
+}
+
+// match builds the match of the current state
+func (m *matcher) match() Match {
+	match := Match{Nodes: make(map[string]*model.Node, len(m.pattern.Nodes)), Edges: make([]*model.Edge, len(m.pattern.Edges))}
+	for _, node := range m.pattern.Nodes {
+		match.Nodes[node.Name] = m.core[node.Index]
+	}
+	for key, edges := range m.patternEdges {
+		matched, _ := m.matchEdges(m.pattern.Nodes[key[0]], m.pattern.Nodes[key[1]])
+		for i, edge := range edges {
+			match.Edges[edge.Index] = matched[i]
+		}
+	}
+	return match
+}
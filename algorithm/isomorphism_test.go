@@ -0,0 +1,121 @@
+package algorithm
+
+import (
+	"dot-parser/model"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const diamond = `digraph {
+	top -> left -> bottom
+	top -> right -> bottom
+}`
+
+// matchedNodes lists the target nodes of each match in the order of the
+// pattern nodes, sorted
+func matchedNodes(pattern *model.Graph, matches []Match) []string {
+	var out []string
+	for _, match := range matches {
+		var nodes []string
+		for _, node := range pattern.Nodes {
+			nodes = append(nodes, match.Nodes[node.Name].Name)
+		}
+		out = append(out, strings.Join(nodes, " "))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestSubgraphIsomorphisms(t *testing.T) {
+	pattern := ParsePattern(diamond).Unwrap()
+	target := resolveGraph(t, `digraph deps {
+		app -> net -> libc
+		app -> ui -> libc
+		ui -> net
+		tool -> net
+	}`)
+
+	got := matchedNodes(pattern, SubgraphIsomorphisms(pattern, target, MatchOptions{}))
+	expected := []string{"app net libc ui", "app ui libc net"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	got = matchedNodes(pattern, SubgraphIsomorphisms(pattern, target, MatchOptions{Induced: true}))
+	if len(got) != 0 {
+		t.Fatalf("Expected no induced diamond, got %v", got)
+	}
+
+	matches := SubgraphIsomorphisms(pattern, target, MatchOptions{Limit: 1})
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	for i, edge := range pattern.Edges {
+		matched := matches[0].Edges[i]
+		if matched.Tail != matches[0].Nodes[edge.Tail.Name] || matched.Head != matches[0].Nodes[edge.Head.Name] {
+			t.Fatalf("Expected pattern edge %d to be matched to an edge between the matched nodes", i)
+		}
+	}
+}
+
+func TestSubgraphIsomorphismsAttributes(t *testing.T) {
+	pattern := ParsePattern(`digraph {
+		node [shape="*"]
+		a [shape=box]
+		a -> b [color=red]
+	}`).Unwrap()
+	target := resolveGraph(t, `digraph {
+		x [shape=box]; y [shape=box]; z [shape=ellipse]; w
+		x -> y [color=red]
+		x -> z [color=red]
+		y -> z [color=blue]
+		x -> w [color=red]
+		z -> x [color=red]
+	}`)
+
+	got := matchedNodes(pattern, SubgraphIsomorphisms(pattern, target, AttributeMatchOptions()))
+	expected := []string{"x y", "x z"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestSubgraphIsomorphismsMultigraph(t *testing.T) {
+	pattern := ParsePattern(`graph { a -- b; a -- b; b -- b }`).Unwrap()
+	target := resolveGraph(t, `graph { x -- y; y -- y; z -- x; y -- x; z -- z; z -- x }`)
+
+	got := matchedNodes(pattern, SubgraphIsomorphisms(pattern, target, MatchOptions{}))
+	expected := []string{"x y", "x z"}
+	if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	if directed := resolveGraph(t, `digraph { x -> y; x -> y; y -> y }`); len(SubgraphIsomorphisms(pattern, directed, MatchOptions{})) != 0 {
+		t.Fatal("Expected an undirected pattern not to match a digraph")
+	}
+}
+
+func TestIsomorphism(t *testing.T) {
+	first := resolveGraph(t, `digraph { a -> b -> c -> a; c -> d }`)
+	second := resolveGraph(t, `digraph { w -> z; x -> y; y -> w; w -> x }`)
+
+	match := Isomorphism(first, second, MatchOptions{})
+	if match.IsNone() {
+		t.Fatal("Expected the graphs to be isomorphic")
+	}
+	expected := map[string]string{"a": "x", "b": "y", "c": "w", "d": "z"}
+	for name, image := range expected {
+		if got := match.Unwrap().Nodes[name].Name; got != image {
+			t.Fatalf("Expected %s to be matched to %s, got %s", name, image, got)
+		}
+	}
+
+	reversed := resolveGraph(t, `digraph { a -> b -> c -> a; d -> c }`)
+	if Isomorphism(first, reversed, MatchOptions{}).IsSome() {
+		t.Fatal("Expected the graphs not to be isomorphic")
+	}
+	extra := resolveGraph(t, `digraph { a -> b -> c -> a; c -> d; d -> a }`)
+	if Isomorphism(first, extra, MatchOptions{}).IsSome() {
+		t.Fatal("Expected graphs with different edge counts not to be isomorphic")
+	}
+}
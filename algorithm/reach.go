@@ -0,0 +1,135 @@
+package algorithm
+
+import (
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"sort"
+)
+
+type Direction uint8
+
+const (
+	// FORWARD follows edges from their tail to their head
+	FORWARD Direction = iota
+	// BACKWARD follows edges from their head to their tail
+	BACKWARD
+	// BOTH follows edges either way
+	BOTH
+)
+
+type ReachOptions struct {
+	// Direction is the way edges of digraphs are followed, edges of
+	// undirected graphs are always followed both ways
+	Direction Direction
+	// MaxDepth is the most edges followed from the start nodes, negative
+	// for no limit
+	MaxDepth int
+	// Node, when not nil, tells the nodes that can be reached: the other
+	// ones are neither reached nor gone through
+	Node func(*model.Node) bool
+	// Edge, when not nil, tells the edges that can be followed
+	Edge func(*model.Edge) bool
+}
+
+func DefaultReachOptions() ReachOptions {
+	return ReachOptions{Direction: FORWARD, MaxDepth: -1}
+}
+
+// next returns the edges that can be followed from a node
+func (options ReachOptions) next(graph *model.Graph, node *model.Node) []*model.Edge {
+	switch {
+	case !graph.IsDirect:
+		return incident(graph, node)
+	case options.Direction == BOTH:
+		return append(append([]*model.Edge(nil), graph.Out(node)...), graph.In(node)...)
+	case options.Direction == BACKWARD:
+		return graph.In(node)
+	default:
+		return graph.Out(node)
+	}
+}
+
+// Reachable returns the nodes that can be reached from the start nodes
+// following at most options.MaxDepth edges, start nodes included, in
+// declaration order. Unknown start nodes give a GraphError.
+func Reachable(graph *model.Graph, start []string, options ReachOptions) Result[[]*model.Node] {
+	depth := make([]int, len(graph.Nodes))
+	for i := range depth {
+		depth[i] = -1
+	}
+
+	var reached []*model.Node
+	for _, name := range start {
+		node, err := lookupNode(graph, name)
+		if err != nil {
+			return Err[[]*model.Node](err)
+		}
+		if depth[node.Index] == -1 {
+			depth[node.Index] = 0
+			reached = append(reached, node)
+		}
+	}
+
+	// reached grows breadth first, so that every node is reached at its
+	// least depth
+	for next := 0; next < len(reached); next++ {
+		node := reached[next]
+		if options.MaxDepth >= 0 && depth[node.Index] >= options.MaxDepth {
+			continue
+		}
+		for _, edge := range options.next(graph, node) {
+			other := edge.Opposite(node)
+			if depth[other.Index] != -1 || (options.Edge != nil && !options.Edge(edge)) || (options.Node != nil && !options.Node(other)) {
+				continue
+			}
+			depth[other.Index] = depth[node.Index] + 1
+			reached = append(reached, other)
+		}
+	}
+
+	sort.Slice(reached, func(i, j int) bool { return reached[i].Index < reached[j].Index })
+	return Ok(reached)
+}
+
+// Descendants returns the nodes that can be reached from a node following
+// at most maxDepth edges forward, negative for no limit, the node itself
+// excluded
+func Descendants(graph *model.Graph, name string, maxDepth int) Result[[]*model.Node] {
+	return relatives(graph, name, ReachOptions{Direction: FORWARD, MaxDepth: maxDepth})
+}
+
+// Ancestors returns the nodes from which a node can be reached following
+// at most maxDepth edges, negative for no limit, the node itself excluded
+func Ancestors(graph *model.Graph, name string, maxDepth int) Result[[]*model.Node] {
+	return relatives(graph, name, ReachOptions{Direction: BACKWARD, MaxDepth: maxDepth})
+}
+
+func relatives(graph *model.Graph, name string, options ReachOptions) Result[[]*model.Node] {
+	return Map(Reachable(graph, []string{name}, options), func(nodes []*model.Node) []*model.Node {
+		relatives := make([]*model.Node, 0, len(nodes))
+		for _, node := range nodes {
+			if node.Name != name {
+				relatives = append(relatives, node)
+			}
+		}
+		return relatives
+	})
+}
+
+// InducedSubgraph returns graph with only the given nodes, which may come
+// from any resolution of graph, and the edges between them. Every other
+// statement about them is kept, in the same order and subgraphs, along
+// with every graph attribute and every default attribute statement;
+// subgraphs left without nodes are left out.
+func InducedSubgraph(graph parser.Graph, nodes []*model.Node) parser.Graph {
+	members := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		members[node.Name] = true
+	}
+
+	filter := makeStatementFilter(func(name string) bool { return members[name] }, func(*parser.Edge) bool { return true })
+	induced := graph
+	induced.Statements = filter.filter(graph.Statements, nil)
+	return induced
+}
@@ -0,0 +1,88 @@
+package algorithm
+
+import (
+	"dot-parser/internal/testutil"
+	"dot-parser/model"
+	"dot-parser/printer"
+	"reflect"
+	"testing"
+)
+
+const build = `digraph build {
+	node [shape=box]
+	app -> lib -> core -> libc
+	app -> ui -> core
+	subgraph cluster_tests {
+		label=tests
+		test -> app
+		test -> mock
+	}
+	edge [style=dashed]
+	ui -> font [optional=true]
+}`
+
+func TestReachable(t *testing.T) {
+	graph := resolveGraph(t, build)
+
+	tests := []struct {
+		start    []string
+		options  ReachOptions
+		expected []string
+	}{
+		{[]string{"ui"}, DefaultReachOptions(), []string{"core", "libc", "ui", "font"}},
+		{[]string{"core"}, ReachOptions{Direction: BACKWARD, MaxDepth: 2}, []string{"app", "lib", "core", "ui"}},
+		{[]string{"mock", "libc"}, ReachOptions{Direction: BACKWARD, MaxDepth: -1}, []string{"app", "lib", "core", "libc", "ui", "test", "mock"}},
+		{[]string{"lib"}, ReachOptions{Direction: BOTH, MaxDepth: 1}, []string{"app", "lib", "core"}},
+		{[]string{"app"}, ReachOptions{MaxDepth: -1, Node: func(node *model.Node) bool { return node.Name != "lib" }},
+			[]string{"app", "core", "libc", "ui", "font"}},
+		{[]string{"app"}, ReachOptions{MaxDepth: -1, Edge: func(edge *model.Edge) bool { return edge.Attributes["optional"] != "true" }},
+			[]string{"app", "lib", "core", "libc", "ui"}},
+	}
+	for _, test := range tests {
+		got := names(Reachable(graph, test.start, test.options).Unwrap())
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Fatalf("Expected %v from %v, got %v", test.expected, test.start, got)
+		}
+	}
+
+	if Reachable(graph, []string{"nowhere"}, DefaultReachOptions()).IsOk() {
+		t.Fatal("Expected a GraphError for an unknown node")
+	}
+}
+
+func TestAncestorsAndDescendants(t *testing.T) {
+	graph := resolveGraph(t, build)
+	if got := names(Ancestors(graph, "core", -1).Unwrap()); !reflect.DeepEqual(got, []string{"app", "lib", "ui", "test"}) {
+		t.Fatalf("Expected the ancestors of core, got %v", got)
+	}
+	if got := names(Descendants(graph, "test", 1).Unwrap()); !reflect.DeepEqual(got, []string{"app", "mock"}) {
+		t.Fatalf("Expected the children of test, got %v", got)
+	}
+
+	cyclic := resolveGraph(t, `digraph { a -> b -> a }`)
+	if got := names(Descendants(cyclic, "a", -1).Unwrap()); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Fatalf("Expected the descendants of a to exclude it, got %v", got)
+	}
+}
+
+func TestInducedSubgraph(t *testing.T) {
+	graph := testutil.ParseGraph(t, build)
+	upstream := Reachable(model.Resolve(graph), []string{"core"}, ReachOptions{Direction: BACKWARD, MaxDepth: 3}).Unwrap()
+
+	expected := `digraph build {
+	node [shape=box]
+	app -> lib
+	lib -> core
+	app -> ui
+	ui -> core
+	subgraph cluster_tests {
+		label=tests
+		test -> app
+	}
+	edge [style=dashed]
+}
+`
+	if printed := printer.Print(InducedSubgraph(graph, upstream)).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+}
@@ -0,0 +1,201 @@
+package algorithm
+
+import (
+	"dot-parser/builder"
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StronglyConnectedComponents returns the strongly connected components of
+// a digraph, computed by an iterative version of Tarjan's algorithm so that
+// deep graphs do not exhaust the stack. Components are ordered by their
+// first declared node and list their nodes in declaration order.
+func StronglyConnectedComponents(graph *model.Graph) Result[[][]*model.Node] {
+	if err := requireDirected(graph, "strongly connected components"); err != nil {
+		return Err[[][]*model.Node](err)
+	}
+	return Ok(tarjan(graph))
+}
+
+func tarjan(graph *model.Graph) [][]*model.Node {
+	counter := 0
+	index := make([]int, len(graph.Nodes))
+	lowlink := make([]int, len(graph.Nodes))
+	onStack := make([]bool, len(graph.Nodes))
+	for i := range index {
+		index[i] = -1
+	}
+
+	var components [][]*model.Node
+	var stack []*model.Node
+	for _, root := range graph.Nodes {
+		if index[root.Index] != -1 {
+			continue
+		}
+
+		frames := []*frame{}
+		push := func(node *model.Node) {
+			index[node.Index], lowlink[node.Index] = counter, counter
+			counter++
+			stack = append(stack, node)
+			onStack[node.Index] = true
+			frames = append(frames, &frame{node: node})
+		}
+		push(root)
+
+		for len(frames) > 0 {
+			top := frames[len(frames)-1]
+			node := top.node
+			if out := graph.Out(node); top.next < len(out) {
+				head := out[top.next].Head
+				top.next++
+				if index[head.Index] == -1 {
+					push(head)
+				} else if onStack[head.Index] && index[head.Index] < lowlink[node.Index] {
+					lowlink[node.Index] = index[head.Index]
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+			if lowlink[node.Index] == index[node.Index] {
+				var component []*model.Node
+				for {
+					member := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[member.Index] = false
+					component = append(component, member)
+					if member == node {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].node
+				if lowlink[node.Index] < lowlink[parent.Index] {
+					lowlink[parent.Index] = lowlink[node.Index]
+				}
+			}
+		}
+	}
+
+	for _, component := range components {
+		sort.Slice(component, func(i, j int) bool { return component[i].Index < component[j].Index })
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0].Index < components[j][0].Index })
+	return components
+}
+
+// componentIndex maps every node to the index of its component
+func componentIndex(graph *model.Graph, components [][]*model.Node) []int {
+	indices := make([]int, len(graph.Nodes))
+	for i, component := range components {
+		for _, node := range component {
+			indices[node.Index] = i
+		}
+	}
+	return indices
+}
+
+// isTrivial reports whether a component is a single node without a loop
+func isTrivial(graph *model.Graph, component []*model.Node) bool {
+	if len(component) > 1 {
+		return false
+	}
+	for _, edge := range graph.Out(component[0]) {
+		if edge.Head == component[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Condensation returns the DAG of the strongly connected components of a
+// digraph, as sccmap does: the component at index i of
+// StronglyConnectedComponents is the node "scc_<i>", labelled with the IDs
+// of its nodes one per line, and the edges between different components are
+// merged into one edge for each pair of components, in the order of the
+// first one.
+func Condensation(graph *model.Graph) Result[parser.Graph] {
+	return Map(StronglyConnectedComponents(graph), func(components [][]*model.Node) parser.Graph {
+		indices := componentIndex(graph, components)
+
+		b := builder.Digraph()
+		if graph.Name.IsSome() {
+			b.Name(graph.Name.Unwrap())
+		}
+		for i, component := range components {
+			b.Node(componentName(i), parser.AttributeMap{"label": componentLabel(component)})
+		}
+
+		added := make(map[[2]int]bool)
+		for _, edge := range graph.Edges {
+			pair := [2]int{indices[edge.Tail.Index], indices[edge.Head.Index]}
+			if pair[0] != pair[1] && !added[pair] {
+				added[pair] = true
+				b.Edge(componentName(pair[0]), componentName(pair[1]))
+			}
+		}
+		return b.Build()
+	})
+}
+
+func componentName(index int) string {
+	return fmt.Sprintf("scc_%d", index)
+}
+
+// componentLabel lists the IDs of the nodes of a component one per line,
+// escaping the backslashes of escString labels
+func componentLabel(component []*model.Node) string {
+	lines := make([]string, len(component))
+	for i, node := range component {
+		lines[i] = strings.ReplaceAll(node.Name, "\\", "\\\\")
+	}
+	return strings.Join(lines, "\\n")
+}
+
+// ComponentClusters returns a digraph with the nodes and edges of graph,
+// with their resolved attributes, where each strongly connected component
+// of more than one node, or with a loop, is the cluster
+// "cluster_scc_<i>", i being the index of the component in
+// StronglyConnectedComponents, holding its nodes and the edges between
+// them. The other nodes and edges are declared outside of the clusters.
+func ComponentClusters(graph *model.Graph) Result[parser.Graph] {
+	return Map(StronglyConnectedComponents(graph), func(components [][]*model.Node) parser.Graph {
+		indices := componentIndex(graph, components)
+		inside := make([][]*model.Edge, len(components))
+		var between []*model.Edge
+		for _, edge := range graph.Edges {
+			if tail := indices[edge.Tail.Index]; tail == indices[edge.Head.Index] {
+				inside[tail] = append(inside[tail], edge)
+			} else {
+				between = append(between, edge)
+			}
+		}
+
+		b := newBuilder(graph)
+		for i, component := range components {
+			if isTrivial(graph, component) {
+				addNode(b, component[0])
+				continue
+			}
+			b.Subgraph(fmt.Sprintf("cluster_scc_%d", i), func(sub *builder.Builder) {
+				for _, node := range component {
+					addNode(sub, node)
+				}
+				for _, edge := range inside[i] {
+					addEdge(sub, edge)
+				}
+			})
+		}
+		for _, edge := range between {
+			addEdge(b, edge)
+		}
+		return b.Build()
+	})
+}
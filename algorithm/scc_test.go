@@ -0,0 +1,106 @@
+package algorithm
+
+import (
+	"dot-parser/builder"
+	"dot-parser/diff"
+	"dot-parser/internal/testutil"
+	"dot-parser/model"
+	"dot-parser/printer"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const components = `digraph G {
+	a -> b -> c -> a
+	c -> d
+	d -> e -> d
+	f -> f
+	g
+	b -> e [color=red]
+}`
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	res := StronglyConnectedComponents(resolveGraph(t, components))
+	if res.IsErr() {
+		t.Fatalf("Expected components, failed with %s", res.UnwrapErr())
+	}
+
+	var found [][]string
+	for _, component := range res.Unwrap() {
+		found = append(found, names(component))
+	}
+	expected := [][]string{{"a", "b", "c"}, {"d", "e"}, {"f"}, {"g"}}
+	if !reflect.DeepEqual(found, expected) {
+		t.Fatalf("Expected %v, got %v", expected, found)
+	}
+}
+
+func TestStronglyConnectedComponentsDeep(t *testing.T) {
+	chain := make([]string, 100001)
+	for i := range chain {
+		chain[i] = fmt.Sprintf("n%d", i%100000)
+	}
+	graph := model.Resolve(builder.Digraph().Path(chain).Build())
+
+	res := StronglyConnectedComponents(graph)
+	if res.IsErr() {
+		t.Fatalf("Expected components, failed with %s", res.UnwrapErr())
+	}
+	if count := len(res.Unwrap()); count != 1 {
+		t.Fatalf("Expected a single component, got %d", count)
+	}
+}
+
+func TestCondensation(t *testing.T) {
+	res := Condensation(resolveGraph(t, components))
+	if res.IsErr() {
+		t.Fatalf("Expected a graph, failed with %s", res.UnwrapErr())
+	}
+
+	expected := testutil.ParseGraph(t, `digraph G {
+		scc_0 [label="a\nb\nc"]
+		scc_1 [label="d\ne"]
+		scc_2 [label="f"]
+		scc_3 [label="g"]
+		scc_0 -> scc_1
+	}`)
+	if !diff.Equal(expected, res.Unwrap()) {
+		t.Fatalf("Unexpected condensation:\n%s", printer.Print(res.Unwrap()).Unwrap())
+	}
+}
+
+func TestComponentClusters(t *testing.T) {
+	res := ComponentClusters(resolveGraph(t, components))
+	if res.IsErr() {
+		t.Fatalf("Expected a graph, failed with %s", res.UnwrapErr())
+	}
+
+	expected := `digraph G {
+	subgraph cluster_scc_0 {
+		a
+		b
+		c
+		a -> b
+		b -> c
+		c -> a
+	}
+	subgraph cluster_scc_1 {
+		d
+		e
+		d -> e
+		e -> d
+	}
+	subgraph cluster_scc_2 {
+		f
+		f -> f
+	}
+	g
+	c -> d
+	b -> e [color=red]
+}
+`
+	if printed := printer.Print(res.Unwrap()).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+}
@@ -0,0 +1,392 @@
+package algorithm
+
+import (
+	"container/heap"
+	"dot-parser/attribute"
+	"dot-parser/builder"
+	. "dot-parser/lexer"
+	"dot-parser/model"
+	"dot-parser/option"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+	"math"
+)
+
+type WeightOptions struct {
+	// Attribute is the attribute holding the weight of an edge, e.g. weight
+	// or len, or of a node
+	Attribute string
+	// Default is the weight of the edges or nodes without the attribute
+	Default float64
+}
+
+func DefaultWeightOptions() WeightOptions {
+	return WeightOptions{Attribute: "weight", Default: 1}
+}
+
+// weights reads the weight of every edge, an invalid weight gives an
+// attribute.AttributeError
+func (options WeightOptions) weights(graph *model.Graph) ([]float64, error) {
+	weights := make([]float64, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		weight, err := options.read(edge.Attributes, edge.Position)
+		if err != nil {
+			return nil, err
+		}
+		weights[i] = weight
+	}
+	return weights, nil
+}
+
+// read reads the weight in the attributes of a node or an edge declared at
+// position
+func (options WeightOptions) read(attributes parser.AttributeMap, position Position) (float64, error) {
+	value, exists := attributes[options.Attribute]
+	if !exists {
+		return options.Default, nil
+	}
+
+	attr := parser.SingleAttribute{Key: options.Attribute, Value: value, Position: position}
+	res := attribute.ParseAttribute(attr, attribute.ParseDouble)
+	if res.IsErr() {
+		return 0, res.UnwrapErr()
+	}
+	if math.IsNaN(res.Unwrap()) {
+		return 0, &attribute.AttributeError{Attribute: attr, Err: &attribute.ValueError{Type: attribute.DOUBLE, Value: value}}
+	}
+	return res.Unwrap(), nil
+}
+
+// Path is a walk through the graph: Edges[i] joins Nodes[i] to Nodes[i+1]
+type Path struct {
+	Nodes []*model.Node
+	Edges []*model.Edge
+	// Cost is the sum of the weights along the path
+	Cost float64
+}
+
+type NegativeCycleError struct {
+	Cycle Cycle
+}
+
+func (err *NegativeCycleError) Error() string {
+	return fmt.Sprintf("graph error: negative cycle %s", err.Cycle)
+}
+
+// ShortestPaths holds the shortest paths from a source node to every node
+// reachable from it
+type ShortestPaths struct {
+	Source *model.Node
+
+	graph    *model.Graph
+	weights  []float64
+	distance []float64
+	// parent holds the last edge of the shortest path to each node
+	parent []*model.Edge
+}
+
+func makeShortestPaths(graph *model.Graph, source *model.Node, weights []float64) *ShortestPaths {
+	paths := &ShortestPaths{
+		Source:   source,
+		graph:    graph,
+		weights:  weights,
+		distance: make([]float64, len(graph.Nodes)),
+		parent:   make([]*model.Edge, len(graph.Nodes)),
+	}
+	for i := range paths.distance {
+		paths.distance[i] = math.Inf(1)
+	}
+	paths.distance[source.Index] = 0
+	return paths
+}
+
+// relax follows edge from node, it returns whether it shortened the path
+// to the other endpoint
+func (paths *ShortestPaths) relax(node *model.Node, edge *model.Edge) bool {
+	other := edge.Opposite(node)
+	distance := paths.distance[node.Index] + paths.weights[edge.Index]
+	if math.IsInf(paths.distance[node.Index], 1) || distance >= paths.distance[other.Index] {
+		return false
+	}
+	paths.distance[other.Index] = distance
+	paths.parent[other.Index] = edge
+	return true
+}
+
+// Distance returns the cost of the shortest path to a node, none when the
+// node does not exist or cannot be reached
+func (paths *ShortestPaths) Distance(name string) option.Option[float64] {
+	node := paths.graph.Node(name)
+	if node.IsNone() || math.IsInf(paths.distance[node.Unwrap().Index], 1) {
+		return option.None[float64]()
+	}
+	return option.Some(paths.distance[node.Unwrap().Index])
+}
+
+// PathTo returns the shortest path to a node, none when the node does not
+// exist or cannot be reached
+func (paths *ShortestPaths) PathTo(name string) option.Option[Path] {
+	if paths.Distance(name).IsNone() {
+		return option.None[Path]()
+	}
+
+	node := paths.graph.Node(name).Unwrap()
+	path := Path{Nodes: []*model.Node{node}, Cost: paths.distance[node.Index]}
+	for node != paths.Source {
+		edge := paths.parent[node.Index]
+		node = edge.Opposite(node)
+		path.Nodes = append(path.Nodes, node)
+		path.Edges = append(path.Edges, edge)
+	}
+
+	for i, j := 0, len(path.Nodes)-1; i < j; i, j = i+1, j-1 {
+		path.Nodes[i], path.Nodes[j] = path.Nodes[j], path.Nodes[i]
+	}
+	for i, j := 0, len(path.Edges)-1; i < j; i, j = i+1, j-1 {
+		path.Edges[i], path.Edges[j] = path.Edges[j], path.Edges[i]
+	}
+	return option.Some(path)
+}
+
+// lookupNode finds a node by name, an unknown name gives a GraphError
+func lookupNode(graph *model.Graph, name string) (*model.Node, error) {
+	node := graph.Node(name)
+	if node.IsNone() {
+		return nil, makeGraphError("unknown node \"%s\"", name)
+	}
+	return node.Unwrap(), nil
+}
+
+// requireNonNegative returns an error for the first edge with a negative
+// weight, on which the algorithm named by name is not defined
+func requireNonNegative(graph *model.Graph, weights []float64, name string) error {
+	for _, edge := range graph.Edges {
+		if weights[edge.Index] < 0 {
+			return makeGraphError("%s requires non-negative weights, the edge from %s to %s at line %d column %d weighs %s",
+				name, edge.Tail.Name, edge.Head.Name, edge.Position.Line(), edge.Position.Column(),
+				attribute.FormatDouble(weights[edge.Index]))
+		}
+	}
+	return nil
+}
+
+// Dijkstra computes the shortest paths from a source node, with the
+// weights read from the edges as options tell. Edges of undirected graphs
+// are followed both ways. Negative weights give a GraphError, Bellman-Ford
+// accepts them. Among paths of the same cost, the one found first
+// following the edges in declaration order is kept.
+func Dijkstra(graph *model.Graph, source string, options WeightOptions) Result[*ShortestPaths] {
+	start, err := lookupNode(graph, source)
+	if err != nil {
+		return Err[*ShortestPaths](err)
+	}
+	weights, err := options.weights(graph)
+	if err != nil {
+		return Err[*ShortestPaths](err)
+	}
+	if err := requireNonNegative(graph, weights, "Dijkstra"); err != nil {
+		return Err[*ShortestPaths](err)
+	}
+
+	paths := makeShortestPaths(graph, start, weights)
+	search(paths, nil, func(*model.Node) float64 { return 0 })
+	return Ok(paths)
+}
+
+// AStar computes the shortest path from a source node to a target node,
+// guided by a heuristic estimating the cost of the path from each node to
+// the target. The path is the shortest one as long as the heuristic never
+// overestimates it; a heuristic that is always 0 makes AStar the same as
+// Dijkstra, whose requirements it shares. Unreachable targets give none.
+func AStar(graph *model.Graph, source string, target string, heuristic func(*model.Node) float64, options WeightOptions) Result[option.Option[Path]] {
+	start, err := lookupNode(graph, source)
+	if err != nil {
+		return Err[option.Option[Path]](err)
+	}
+	goal, err := lookupNode(graph, target)
+	if err != nil {
+		return Err[option.Option[Path]](err)
+	}
+	weights, err := options.weights(graph)
+	if err != nil {
+		return Err[option.Option[Path]](err)
+	}
+	if err := requireNonNegative(graph, weights, "A*"); err != nil {
+		return Err[option.Option[Path]](err)
+	}
+
+	paths := makeShortestPaths(graph, start, weights)
+	search(paths, goal, heuristic)
+	return Ok(paths.PathTo(target))
+}
+
+// search runs A* from the source of paths until target, or every node when
+// target is nil, is settled. Nodes are reopened when a shorter path to
+// them is found, so that admissible heuristics are enough.
+func search(paths *ShortestPaths, target *model.Node, heuristic func(*model.Node) float64) {
+	graph := paths.graph
+	open := &distanceHeap{{index: paths.Source.Index, priority: heuristic(paths.Source)}}
+	for open.Len() > 0 {
+		entry := heap.Pop(open).(distanceEntry)
+		node := graph.Nodes[entry.index]
+		if entry.distance > paths.distance[node.Index] {
+			continue
+		}
+		if node == target {
+			return
+		}
+
+		for _, edge := range incident(graph, node) {
+			if paths.relax(node, edge) {
+				other := edge.Opposite(node)
+				distance := paths.distance[other.Index]
+				heap.Push(open, distanceEntry{index: other.Index, distance: distance, priority: distance + heuristic(other)})
+			}
+		}
+	}
+}
+
+type distanceEntry struct {
+	index    int
+	distance float64
+	priority float64
+}
+
+// distanceHeap is a min-heap of nodes by priority, then by index
+type distanceHeap []distanceEntry
+
+func (h distanceHeap) Len() int { return len(h) }
+func (h distanceHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority < h[j].priority
+	}
+	return h[i].index < h[j].index
+}
+func (h distanceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *distanceHeap) Push(x interface{}) { *h = append(*h, x.(distanceEntry)) }
+func (h *distanceHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// BellmanFord computes the shortest paths from a source node, with the
+// weights read from the edges as options tell, which may be negative.
+// Edges of undirected graphs are followed both ways, so that a negative
+// undirected edge is a negative cycle. A negative cycle reachable from the
+// source gives a NegativeCycleError holding it.
+func BellmanFord(graph *model.Graph, source string, options WeightOptions) Result[*ShortestPaths] {
+	start, err := lookupNode(graph, source)
+	if err != nil {
+		return Err[*ShortestPaths](err)
+	}
+	weights, err := options.weights(graph)
+	if err != nil {
+		return Err[*ShortestPaths](err)
+	}
+
+	paths := makeShortestPaths(graph, start, weights)
+	relaxAll := func() option.Option[*model.Node] {
+		changed := option.None[*model.Node]()
+		for _, edge := range graph.Edges {
+			if paths.relax(edge.Tail, edge) {
+				changed = option.Some(edge.Head)
+			}
+			if !graph.IsDirect && paths.relax(edge.Head, edge) {
+				changed = option.Some(edge.Tail)
+			}
+		}
+		return changed
+	}
+
+	// After a pass that changes nothing no later pass would, and without
+	// negative cycles every shortest path is found by len(graph.Nodes)-1
+	// passes
+	for i := 1; i < len(graph.Nodes); i++ {
+		if relaxAll().IsNone() {
+			return Ok(paths)
+		}
+	}
+	if changed := relaxAll(); changed.IsSome() {
+		return Err[*ShortestPaths](&NegativeCycleError{Cycle: parentCycle(paths, changed.Unwrap())})
+	}
+	return Ok(paths)
+}
+
+// parentCycle returns the cycle of parent edges reached going back from a
+// node whose distance changed after len(graph.Nodes)-1 passes
+func parentCycle(paths *ShortestPaths, node *model.Node) Cycle {
+	// Going back as many steps as there are nodes surely enters the cycle
+	for range paths.graph.Nodes {
+		node = paths.parent[node.Index].Opposite(node)
+	}
+
+	nodes := []string{}
+	edges := []*model.Edge{}
+	for current := node; len(nodes) == 0 || current != node; {
+		edge := paths.parent[current.Index]
+		current = edge.Opposite(current)
+		nodes = append(nodes, current.Name)
+		edges = append(edges, edge)
+	}
+
+	// The cycle was built backwards: edges[i] joins nodes[i] to the node
+	// before it
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+	return Cycle{Nodes: nodes, Edges: edges, IsDirect: paths.graph.IsDirect}
+}
+
+// Highlight returns graph with attributes added to the statements of the
+// nodes and of the edges of path, e.g. parser.AttributeMap{"color": "red"}.
+// Edge statements get the attributes as an additional attribute list,
+// nodes as node statements at the end of the graph, so that every other
+// statement and the subgraphs nodes belong to are kept.
+func Highlight(graph parser.Graph, path Path, attributes parser.AttributeMap) parser.Graph {
+	marked := make(map[*parser.Edge]bool)
+	for _, edge := range path.Edges {
+		for _, stmt := range edge.Statements {
+			marked[stmt] = true
+		}
+	}
+
+	highlighted := graph
+	highlighted.Statements = withEdgeAttributes(graph.Statements, marked, attributes)
+	seen := make(map[*model.Node]bool)
+	for _, node := range path.Nodes {
+		if !seen[node] {
+			seen[node] = true
+			highlighted.Statements = append(highlighted.Statements,
+				&parser.Node{ID: builder.ID(node.Name), Attributes: []parser.AttributeMap{merged(attributes, nil)}})
+		}
+	}
+	return highlighted
+}
+
+// withEdgeAttributes copies a statement list, adding attributes to the
+// marked edge statements
+func withEdgeAttributes(stmts []parser.Statement, marked map[*parser.Edge]bool, attributes parser.AttributeMap) []parser.Statement {
+	copied := make([]parser.Statement, len(stmts))
+	for i, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *parser.Edge:
+			if marked[stmt] {
+				edge := *stmt
+				edge.Attributes = append(append([]parser.AttributeMap(nil), stmt.Attributes...), merged(attributes, nil))
+				copied[i] = &edge
+				continue
+			}
+		case *parser.Subgraph:
+			subgraph := *stmt
+			subgraph.Statements = withEdgeAttributes(stmt.Statements, marked, attributes)
+			copied[i] = &subgraph
+			continue
+		}
+		copied[i] = stmt
+	}
+	return copied
+}
@@ -0,0 +1,165 @@
+package algorithm
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/internal/testutil"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"dot-parser/printer"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const roads = `digraph roads {
+	a -> b [weight=4]
+	a -> c [weight=1]
+	c -> b [weight=2]
+	b -> d [weight=1]
+	c -> d [weight=5]
+	e
+}`
+
+func TestDijkstra(t *testing.T) {
+	graph := resolveGraph(t, roads)
+	paths := Dijkstra(graph, "a", DefaultWeightOptions()).Unwrap()
+
+	path := paths.PathTo("d").Unwrap()
+	if !reflect.DeepEqual(names(path.Nodes), []string{"a", "c", "b", "d"}) || path.Cost != 4 {
+		t.Fatalf("Expected a, c, b, d costing 4, got %v costing %v", names(path.Nodes), path.Cost)
+	}
+	if len(path.Edges) != 3 || path.Edges[1].Tail.Name != "c" || path.Edges[1].Head.Name != "b" {
+		t.Fatalf("Expected the edges of the path, got %v", path.Edges)
+	}
+	if paths.PathTo("e").IsSome() || paths.Distance("e").IsSome() || paths.PathTo("z").IsSome() {
+		t.Fatal("Expected no path to e and z")
+	}
+	if paths.Distance("a").Unwrap() != 0 {
+		t.Fatalf("Expected a to be at distance 0, got %v", paths.Distance("a").Unwrap())
+	}
+
+	lengths := Dijkstra(graph, "a", WeightOptions{Attribute: "len", Default: 1}).Unwrap()
+	if !reflect.DeepEqual(names(lengths.PathTo("d").Unwrap().Nodes), []string{"a", "b", "d"}) {
+		t.Fatalf("Expected a, b, d, got %v", names(lengths.PathTo("d").Unwrap().Nodes))
+	}
+
+	undirected := resolveGraph(t, `graph { a -- b [weight=1]; c -- b [weight=1]; a -- c [weight=3] }`)
+	if cost := Dijkstra(undirected, "c", DefaultWeightOptions()).Unwrap().Distance("a").Unwrap(); cost != 2 {
+		t.Fatalf("Expected c to be at distance 2 from a, got %v", cost)
+	}
+}
+
+func TestDijkstraErrors(t *testing.T) {
+	graph := resolveGraph(t, `digraph { a -> b [weight=-1]; b -> c [len=far] }`)
+
+	if _, ok := Dijkstra(graph, "z", DefaultWeightOptions()).UnwrapErr().(*GraphError); !ok {
+		t.Fatal("Expected a GraphError for an unknown source")
+	}
+	if _, ok := Dijkstra(graph, "a", DefaultWeightOptions()).UnwrapErr().(*GraphError); !ok {
+		t.Fatal("Expected a GraphError for a negative weight")
+	}
+
+	err := Dijkstra(graph, "a", WeightOptions{Attribute: "len", Default: 1}).UnwrapErr()
+	var attributeErr *attribute.AttributeError
+	if !errors.As(err, &attributeErr) || attributeErr.Attribute.Value != "far" || attributeErr.Attribute.Position.Line() != 1 {
+		t.Fatalf("Expected an AttributeError for the invalid weight, got %s", err)
+	}
+}
+
+func TestBellmanFord(t *testing.T) {
+	graph := resolveGraph(t, `digraph {
+		a -> b [weight=4]
+		a -> c [weight=5]
+		c -> b [weight=-3]
+		b -> d [weight=1]
+	}`)
+	path := BellmanFord(graph, "a", DefaultWeightOptions()).Unwrap().PathTo("d").Unwrap()
+	if !reflect.DeepEqual(names(path.Nodes), []string{"a", "c", "b", "d"}) || path.Cost != 3 {
+		t.Fatalf("Expected a, c, b, d costing 3, got %v costing %v", names(path.Nodes), path.Cost)
+	}
+
+	cyclic := resolveGraph(t, `digraph {
+		s -> a
+		a -> b [weight=2]
+		b -> c [weight=-4]
+		c -> a [weight=1]
+		c -> t
+	}`)
+	err, ok := BellmanFord(cyclic, "s", DefaultWeightOptions()).UnwrapErr().(*NegativeCycleError)
+	if !ok {
+		t.Fatal("Expected a NegativeCycleError")
+	}
+	cycle := err.Cycle
+	if len(cycle.Nodes) != 3 || len(cycle.Edges) != 3 {
+		t.Fatalf("Expected a cycle of three nodes, got %s", cycle)
+	}
+	for i, edge := range cycle.Edges {
+		if edge.Tail.Name != cycle.Nodes[i] || edge.Head.Name != cycle.Nodes[(i+1)%len(cycle.Nodes)] {
+			t.Fatalf("Expected edge %d to join %s to the next node, got %s", i, cycle.Nodes[i], cycle)
+		}
+	}
+
+	// The cycle cannot be reached from t
+	if BellmanFord(cyclic, "t", DefaultWeightOptions()).IsErr() {
+		t.Fatal("Expected no error from t")
+	}
+
+	undirected := resolveGraph(t, `graph { a -- b [weight=-1] }`)
+	if _, ok := BellmanFord(undirected, "a", DefaultWeightOptions()).UnwrapErr().(*NegativeCycleError); !ok {
+		t.Fatal("Expected a negative undirected edge to be a negative cycle")
+	}
+}
+
+func TestAStar(t *testing.T) {
+	graph := resolveGraph(t, `graph grid {
+		a -- b; b -- c
+		a -- d; d -- e; e -- f
+		c -- f
+		d -- x [weight=10]
+	}`)
+	// The rows of the nodes of a 2x3 grid
+	rows := map[string]float64{"a": 0, "b": 0, "c": 0, "d": 1, "e": 1, "f": 1, "x": 2}
+	heuristic := func(node *model.Node) float64 { return 1 - rows[node.Name] }
+
+	path := AStar(graph, "a", "f", heuristic, DefaultWeightOptions()).Unwrap().Unwrap()
+	if path.Cost != 3 || len(path.Edges) != 3 {
+		t.Fatalf("Expected a path costing 3, got %v costing %v", names(path.Nodes), path.Cost)
+	}
+
+	lonely := resolveGraph(t, `graph { a -- b; c }`)
+	if AStar(lonely, "a", "c", heuristic, DefaultWeightOptions()).Unwrap().IsSome() {
+		t.Fatal("Expected no path to c")
+	}
+	if AStar(lonely, "a", "z", heuristic, DefaultWeightOptions()).IsOk() {
+		t.Fatal("Expected an error for an unknown target")
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+	a -> b
+	subgraph cluster_x {
+		b -> c [weight=1]
+	}
+	a -> c [weight=5]
+}`)
+	path := Dijkstra(model.Resolve(graph), "a", DefaultWeightOptions()).Unwrap().PathTo("c").Unwrap()
+
+	expected := `digraph {
+	a -> b [color=red]
+	subgraph cluster_x {
+		b -> c [weight=1] [color=red]
+	}
+	a -> c [weight=5]
+	a [color=red]
+	b [color=red]
+	c [color=red]
+}
+`
+	if printed := printer.Print(Highlight(graph, path, parser.AttributeMap{"color": "red"})).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+	if printed := printer.Print(graph).Unwrap(); printed == expected {
+		t.Fatal("Expected the input graph to be left as it is")
+	}
+}
@@ -0,0 +1,103 @@
+package algorithm
+
+import (
+	"dot-parser/model"
+	"dot-parser/option"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+	"sort"
+)
+
+// ConnectedComponents returns the weakly connected components of a graph,
+// the connected components when it is undirected: the sets of nodes joined
+// by edges followed either way. Components are ordered by their first
+// declared node and list their nodes in declaration order.
+func ConnectedComponents(graph *model.Graph) [][]*model.Node {
+	component := make([]int, len(graph.Nodes))
+	for i := range component {
+		component[i] = -1
+	}
+
+	var components [][]*model.Node
+	for _, root := range graph.Nodes {
+		if component[root.Index] != -1 {
+			continue
+		}
+
+		index := len(components)
+		component[root.Index] = index
+		members := []*model.Node{root}
+		for next := 0; next < len(members); next++ {
+			node := members[next]
+			for _, edges := range [][]*model.Edge{graph.Out(node), graph.In(node)} {
+				for _, edge := range edges {
+					if other := edge.Opposite(node); component[other.Index] == -1 {
+						component[other.Index] = index
+						members = append(members, other)
+					}
+				}
+			}
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].Index < members[j].Index })
+		components = append(components, members)
+	}
+	return components
+}
+
+type SplitOptions struct {
+	// Strong splits digraphs in strongly connected components instead of
+	// weakly connected ones, dropping the edges between components
+	Strong bool
+	// Largest keeps only the Largest components with the most nodes, the
+	// first declared ones among components of the same size; 0 keeps all
+	Largest int
+}
+
+// Split splits a graph in one graph per component, as ccomps does: each
+// graph is the InducedSubgraph of a component. The graphs are in the order
+// of the components, and named after graph, followed by the index of the
+// component among the returned ones, unless graph is anonymous. Strong
+// splits of undirected graphs give a GraphError.
+func Split(graph parser.Graph, options SplitOptions) Result[[]parser.Graph] {
+	resolved := model.Resolve(graph)
+	components := Ok(ConnectedComponents(resolved))
+	if options.Strong {
+		components = StronglyConnectedComponents(resolved)
+	}
+
+	return Map(components, func(components [][]*model.Node) []parser.Graph {
+		components = largest(components, options.Largest)
+
+		graphs := make([]parser.Graph, len(components))
+		for i, component := range components {
+			graphs[i] = InducedSubgraph(graph, component)
+			if graph.Name.IsSome() {
+				graphs[i].Name = option.Some(fmt.Sprintf("%s_%d", graph.Name.Unwrap(), i))
+			}
+		}
+		return graphs
+	})
+}
+
+// largest keeps the count components with the most nodes, in their
+// original order, or all of them when count is not positive
+func largest(components [][]*model.Node, count int) [][]*model.Node {
+	if count <= 0 || count >= len(components) {
+		return components
+	}
+
+	order := make([]int, len(components))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return len(components[order[i]]) > len(components[order[j]]) })
+	order = order[:count]
+	sort.Ints(order)
+
+	kept := make([][]*model.Node, count)
+	for i, index := range order {
+		kept[i] = components[index]
+	}
+	return kept
+}
@@ -0,0 +1,106 @@
+package algorithm
+
+import (
+	"dot-parser/internal/testutil"
+	"dot-parser/printer"
+	"reflect"
+	"testing"
+)
+
+const islands = `digraph G {
+	rankdir=LR
+	node [shape=box]
+	a -> b
+	subgraph cluster_x {
+		label=x
+		c -> d
+		d -> c
+	}
+	edge [color=red]
+	b -> a
+	e
+	d -> f
+}`
+
+func TestConnectedComponents(t *testing.T) {
+	graph := resolveGraph(t, islands)
+	var got [][]string
+	for _, component := range ConnectedComponents(graph) {
+		got = append(got, names(component))
+	}
+	expected := [][]string{{"a", "b"}, {"c", "d", "f"}, {"e"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	graphs := Split(testutil.ParseGraph(t, islands), SplitOptions{}).Unwrap()
+	expected := []string{`digraph G_0 {
+	rankdir=LR
+	node [shape=box]
+	a -> b
+	edge [color=red]
+	b -> a
+}
+`, `digraph G_1 {
+	rankdir=LR
+	node [shape=box]
+	subgraph cluster_x {
+		label=x
+		c -> d
+		d -> c
+	}
+	edge [color=red]
+	d -> f
+}
+`, `digraph G_2 {
+	rankdir=LR
+	node [shape=box]
+	edge [color=red]
+	e
+}
+`}
+	if len(graphs) != len(expected) {
+		t.Fatalf("Expected %d graphs, got %d", len(expected), len(graphs))
+	}
+	for i, graph := range graphs {
+		if printed := printer.Print(graph).Unwrap(); printed != expected[i] {
+			t.Fatalf("Expected:\n%s\ngot:\n%s", expected[i], printed)
+		}
+	}
+}
+
+func TestSplitStrongLargest(t *testing.T) {
+	graphs := Split(testutil.ParseGraph(t, islands), SplitOptions{Strong: true, Largest: 2}).Unwrap()
+	expected := []string{`digraph G_0 {
+	rankdir=LR
+	node [shape=box]
+	a -> b
+	edge [color=red]
+	b -> a
+}
+`, `digraph G_1 {
+	rankdir=LR
+	node [shape=box]
+	subgraph cluster_x {
+		label=x
+		c -> d
+		d -> c
+	}
+	edge [color=red]
+}
+`}
+	if len(graphs) != len(expected) {
+		t.Fatalf("Expected %d graphs, got %d", len(expected), len(graphs))
+	}
+	for i, graph := range graphs {
+		if printed := printer.Print(graph).Unwrap(); printed != expected[i] {
+			t.Fatalf("Expected:\n%s\ngot:\n%s", expected[i], printed)
+		}
+	}
+
+	if Split(testutil.ParseGraph(t, `graph { a -- b }`), SplitOptions{Strong: true}).IsOk() {
+		t.Fatal("Expected a GraphError for an undirected graph")
+	}
+}
@@ -0,0 +1,212 @@
+package algorithm
+
+import (
+	"container/heap"
+	. "dot-parser/lexer"
+	"dot-parser/model"
+	"dot-parser/option"
+	. "dot-parser/result"
+	"fmt"
+	"strings"
+)
+
+// Cycle is a closed path: Edges[i] joins Nodes[i] to the next node, the
+// last edge goes back to the first node
+type Cycle struct {
+	Nodes []string
+	Edges []*model.Edge
+	// IsDirect tells whether the edges are directed
+	IsDirect bool
+}
+
+// Positions returns the source positions of the edges of the cycle
+func (cycle Cycle) Positions() []Position {
+	positions := make([]Position, len(cycle.Edges))
+	for i, edge := range cycle.Edges {
+		positions[i] = edge.Position
+	}
+	return positions
+}
+
+func (cycle Cycle) String() string {
+	arc := " -- "
+	if cycle.IsDirect {
+		arc = " -> "
+	}
+	steps := make([]string, len(cycle.Edges))
+	for i, edge := range cycle.Edges {
+		next := cycle.Nodes[(i+1)%len(cycle.Nodes)]
+		steps[i] = fmt.Sprintf("%s%s%s (%d:%d)", cycle.Nodes[i], arc, next, edge.Position.Line(), edge.Position.Column())
+	}
+	return strings.Join(steps, ", ")
+}
+
+type CycleError struct {
+	Cycle Cycle
+}
+
+func (err *CycleError) Error() string {
+	return fmt.Sprintf("graph error: cycle %s", err.Cycle)
+}
+
+// indexHeap is a min-heap of node indices
+type indexHeap []int
+
+func (h indexHeap) Len() int            { return len(h) }
+func (h indexHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// TopologicalSort orders the nodes of a digraph so that every edge goes
+// from a node to a later one. Among the nodes that can come next, the one
+// declared first is chosen, so that the order only departs from declaration
+// order where edges require it. Graphs with a cycle give a CycleError
+// holding one of the cycles, undirected graphs a GraphError.
+func TopologicalSort(graph *model.Graph) Result[[]*model.Node] {
+	if err := requireDirected(graph, "topological sort"); err != nil {
+		return Err[[]*model.Node](err)
+	}
+
+	inDegree := make([]int, len(graph.Nodes))
+	ready := &indexHeap{}
+	for _, node := range graph.Nodes {
+		inDegree[node.Index] = len(graph.In(node))
+		if inDegree[node.Index] == 0 {
+			*ready = append(*ready, node.Index)
+		}
+	}
+	heap.Init(ready)
+
+	order := make([]*model.Node, 0, len(graph.Nodes))
+	for ready.Len() > 0 {
+		node := graph.Nodes[heap.Pop(ready).(int)]
+		order = append(order, node)
+		for _, edge := range graph.Out(node) {
+			inDegree[edge.Head.Index]--
+			if inDegree[edge.Head.Index] == 0 {
+				heap.Push(ready, edge.Head.Index)
+			}
+		}
+	}
+
+	if len(order) < len(graph.Nodes) {
+		return Err[[]*model.Node](&CycleError{Cycle: FindCycle(graph).Unwrap()})
+	}
+	return Ok(order)
+}
+
+// visit states of the depth-first searches
+const (
+	UNVISITED = iota
+	ACTIVE
+	DONE
+)
+
+// frame is a node on the stack of an iterative depth-first search, next
+// being the index of the next of its edges to follow
+type frame struct {
+	node *model.Node
+	next int
+	// edges, when set, holds the edges of the node, computed once when it
+	// is pushed
+	edges []*model.Edge
+}
+
+// FindCycle returns the first cycle met by a depth-first search that starts
+// from the nodes and follows the edges in declaration order, none when the
+// graph is acyclic. Edges of undirected graphs are followed both ways, but
+// never twice in a row, so that two parallel edges or a loop are a cycle
+// and a single edge is not.
+func FindCycle(graph *model.Graph) option.Option[Cycle] {
+	state := make([]int, len(graph.Nodes))
+	// parent holds the edge a node was reached by
+	parent := make([]*model.Edge, len(graph.Nodes))
+
+	for _, root := range graph.Nodes {
+		if state[root.Index] != UNVISITED {
+			continue
+		}
+
+		frames := []*frame{{node: root, edges: incident(graph, root)}}
+		state[root.Index] = ACTIVE
+		for len(frames) > 0 {
+			top := frames[len(frames)-1]
+			node := top.node
+			if top.next == len(top.edges) {
+				state[node.Index] = DONE
+				frames = frames[:len(frames)-1]
+				continue
+			}
+
+			edge := top.edges[top.next]
+			top.next++
+			if edge == parent[node.Index] && !graph.IsDirect {
+				continue
+			}
+			other := edge.Opposite(node)
+			switch state[other.Index] {
+			case UNVISITED:
+				parent[other.Index] = edge
+				state[other.Index] = ACTIVE
+				frames = append(frames, &frame{node: other, edges: incident(graph, other)})
+			case ACTIVE:
+				return option.Some(closeCycle(graph, node, other, edge, parent))
+			}
+		}
+	}
+	return option.None[Cycle]()
+}
+
+// incident returns the edges leaving a node, and the ones entering it in
+// undirected graphs, in declaration order
+func incident(graph *model.Graph, node *model.Node) []*model.Edge {
+	if graph.IsDirect {
+		return graph.Out(node)
+	}
+
+	out, in := graph.Out(node), graph.In(node)
+	edges := make([]*model.Edge, 0, len(out)+len(in))
+	i, j := 0, 0
+	for i < len(out) || j < len(in) {
+		if j == len(in) || (i < len(out) && out[i].Index < in[j].Index) {
+			edges = append(edges, out[i])
+			i++
+		} else {
+			// a loop is both in out and in, it is only listed once
+			if in[j].Tail != in[j].Head {
+				edges = append(edges, in[j])
+			}
+			j++
+		}
+	}
+	return edges
+}
+
+// closeCycle builds the cycle closed by the edge from node to one of its
+// active ancestors, following the parent edges from node back to ancestor
+func closeCycle(graph *model.Graph, node *model.Node, ancestor *model.Node, back *model.Edge, parent []*model.Edge) Cycle {
+	nodes := []string{node.Name}
+	edges := []*model.Edge{back}
+	for current := node; current != ancestor; {
+		edge := parent[current.Index]
+		current = edge.Opposite(current)
+		nodes = append(nodes, current.Name)
+		edges = append(edges, edge)
+	}
+
+	// The path was built backwards, from node to ancestor, and back closes it
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	for i, j := 1, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+	edges = append(edges[1:], edges[0])
+	return Cycle{Nodes: nodes, Edges: edges, IsDirect: graph.IsDirect}
+}
@@ -0,0 +1,117 @@
+package algorithm
+
+import (
+	"dot-parser/builder"
+	"dot-parser/internal/testutil"
+	"dot-parser/model"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func resolveGraph(t *testing.T, input string) *model.Graph {
+	return model.Resolve(testutil.ParseGraph(t, input))
+}
+
+func names(nodes []*model.Node) []string {
+	out := make([]string, len(nodes))
+	for i, node := range nodes {
+		out[i] = node.Name
+	}
+	return out
+}
+
+func TestTopologicalSort(t *testing.T) {
+	graph := resolveGraph(t, `digraph {
+		e; d; c
+		c -> a
+		d -> b -> a
+		e -> c
+	}`)
+
+	res := TopologicalSort(graph)
+	if res.IsErr() {
+		t.Fatalf("Expected an order, failed with %s", res.UnwrapErr())
+	}
+	expected := []string{"e", "d", "c", "b", "a"}
+	if order := names(res.Unwrap()); !reflect.DeepEqual(order, expected) {
+		t.Fatalf("Expected %v, got %v", expected, order)
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	graph := resolveGraph(t, `digraph {
+		start -> a
+		a -> b
+		b -> c
+		c -> a
+	}`)
+
+	res := TopologicalSort(graph)
+	if res.IsOk() {
+		t.Fatalf("Expected a cycle, got %v", names(res.Unwrap()))
+	}
+	err, ok := res.UnwrapErr().(*CycleError)
+	if !ok {
+		t.Fatalf("Expected a CycleError, got %s", res.UnwrapErr())
+	}
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(err.Cycle.Nodes, expected) {
+		t.Fatalf("Expected cycle %v, got %v", expected, err.Cycle.Nodes)
+	}
+
+	lines := []int{}
+	for _, position := range err.Cycle.Positions() {
+		lines = append(lines, position.Line())
+	}
+	if expected := []int{3, 4, 5}; !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("Expected edges on lines %v, got %v", expected, lines)
+	}
+	if expected := "graph error: cycle a -> b (3:5), b -> c (4:5), c -> a (5:5)"; err.Error() != expected {
+		t.Fatalf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestTopologicalSortUndirected(t *testing.T) {
+	if res := TopologicalSort(resolveGraph(t, `graph { a -- b }`)); res.IsOk() {
+		t.Fatal("Expected an error for an undirected graph")
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{`digraph { a -> b; b -> c; a -> c }`, nil},
+		{`digraph { a -> b; b -> b }`, []string{"b"}},
+		{`graph { a -- b; b -- c }`, nil},
+		{`graph { a -- b; b -- c; c -- a }`, []string{"a", "b", "c"}},
+		{`graph { x; a -- b; a -- b }`, []string{"a", "b"}},
+	}
+
+	for _, c := range cases {
+		cycle := FindCycle(resolveGraph(t, c.input))
+		if c.expected == nil {
+			if cycle.IsSome() {
+				t.Errorf("Expected no cycle in %s, got %v", c.input, cycle.Unwrap().Nodes)
+			}
+			continue
+		}
+		if cycle.IsNone() {
+			t.Errorf("Expected cycle %v in %s, got none", c.expected, c.input)
+		} else if !reflect.DeepEqual(cycle.Unwrap().Nodes, c.expected) {
+			t.Errorf("Expected cycle %v in %s, got %v", c.expected, c.input, cycle.Unwrap().Nodes)
+		}
+	}
+}
+
+func TestFindCycleHub(t *testing.T) {
+	b := builder.Graph()
+	for i := 0; i < 100000; i++ {
+		b.Edge("hub", fmt.Sprintf("n%d", i))
+	}
+	graph := model.Resolve(b.Build())
+	if cycle := FindCycle(graph); cycle.IsSome() {
+		t.Fatalf("Expected no cycle in a star, got %s", cycle.Unwrap())
+	}
+}
@@ -0,0 +1,98 @@
+package algorithm
+
+import (
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"sort"
+)
+
+// bitset is a set of node indices
+type bitset []uint64
+
+func makeBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (set bitset) add(index int) {
+	set[index/64] |= 1 << (index % 64)
+}
+
+func (set bitset) contains(index int) bool {
+	return set[index/64]&(1<<(index%64)) != 0
+}
+
+func (set bitset) union(other bitset) {
+	for i := range set {
+		set[i] |= other[i]
+	}
+}
+
+// RedundantEdges returns the edges of a DAG that a transitive reduction
+// removes, in declaration order: the edges from u to v such that v can also
+// be reached from u through other edges, and the repetitions of an edge
+// from u to v. The transitive reduction of a graph with cycles is not
+// unique, so they give a CycleError holding one of them.
+func RedundantEdges(graph *model.Graph) Result[[]*model.Edge] {
+	order := TopologicalSort(graph)
+	if order.IsErr() {
+		return Err[[]*model.Edge](order.UnwrapErr())
+	}
+
+	position := make([]int, len(graph.Nodes))
+	for i, node := range order.Unwrap() {
+		position[node.Index] = i
+	}
+
+	redundant := make([]bool, len(graph.Edges))
+	// reach holds the nodes reachable from each node through at least one
+	// edge, the successors of a node are visited before it
+	reach := make([]bitset, len(graph.Nodes))
+	nodes := order.Unwrap()
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		reach[node.Index] = makeBitset(len(graph.Nodes))
+
+		// A successor reachable from an earlier successor, in topological
+		// order, is reachable through it
+		out := append([]*model.Edge(nil), graph.Out(node)...)
+		sort.SliceStable(out, func(i, j int) bool { return position[out[i].Head.Index] < position[out[j].Head.Index] })
+		for _, edge := range out {
+			if reach[node.Index].contains(edge.Head.Index) {
+				redundant[edge.Index] = true
+				continue
+			}
+			reach[node.Index].add(edge.Head.Index)
+			reach[node.Index].union(reach[edge.Head.Index])
+		}
+	}
+
+	var edges []*model.Edge
+	for _, edge := range graph.Edges {
+		if redundant[edge.Index] {
+			edges = append(edges, edge)
+		}
+	}
+	return Ok(edges)
+}
+
+// TransitiveReduction returns graph without its redundant edges, as tred
+// does. Every other statement is kept as it is, in the same order; a
+// removed edge statement is replaced by node statements for the endpoints
+// it was the first mention of, in the graph or in its subgraph, so that
+// nodes keep their attributes, their order and their subgraphs.
+func TransitiveReduction(graph parser.Graph) Result[parser.Graph] {
+	return Map(RedundantEdges(model.Resolve(graph)), func(edges []*model.Edge) parser.Graph {
+		removed := make(map[*parser.Edge]bool)
+		for _, edge := range edges {
+			for _, stmt := range edge.Statements {
+				removed[stmt] = true
+			}
+		}
+
+		filter := makeStatementFilter(func(string) bool { return true }, func(stmt *parser.Edge) bool { return !removed[stmt] })
+		reduced := graph
+		reduced.Statements = filter.filter(graph.Statements, nil)
+		return reduced
+	})
+}
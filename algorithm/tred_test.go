@@ -0,0 +1,59 @@
+package algorithm
+
+import (
+	"dot-parser/internal/testutil"
+	"dot-parser/printer"
+	"testing"
+)
+
+func TestTransitiveReduction(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph deps {
+	node [shape=box]
+	app -> lib [color=blue]
+	app -> core
+	subgraph cluster_lib {
+		node [color=red]
+		lib -> core
+		lib -> util
+		lib -> util
+		util -> core
+	}
+	app -> util [style=dashed]
+}`)
+
+	expected := `digraph deps {
+	node [shape=box]
+	app -> lib [color=blue]
+	core
+	subgraph cluster_lib {
+		node [color=red]
+		lib
+		core
+		lib -> util
+		util -> core
+	}
+}
+`
+	res := TransitiveReduction(graph)
+	if res.IsErr() {
+		t.Fatalf("Expected a graph, failed with %s", res.UnwrapErr())
+	}
+	if printed := printer.Print(res.Unwrap()).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+
+	redundant := RedundantEdges(resolveGraph(t, printer.Print(graph).Unwrap()))
+	if count := len(redundant.Unwrap()); count != 4 {
+		t.Fatalf("Expected 4 redundant edges, got %d", count)
+	}
+}
+
+func TestTransitiveReductionCycle(t *testing.T) {
+	res := TransitiveReduction(testutil.ParseGraph(t, `digraph { a -> b -> c -> a; a -> c }`))
+	if res.IsOk() {
+		t.Fatal("Expected a cycle error")
+	}
+	if _, ok := res.UnwrapErr().(*CycleError); !ok {
+		t.Fatalf("Expected a CycleError, got %s", res.UnwrapErr())
+	}
+}
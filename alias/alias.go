@@ -0,0 +1,131 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aliases gives each DOT ID an identifier made of ASCII letters, digits and
+// underscores, not starting with a digit, for the formats whose identifiers
+// are more restricted than DOT IDs. An ID that already is such an
+// identifier, and is not reserved, is its own alias; any other ID gets a
+// sanitised alias made unique with a numeric suffix. The same ID always gets
+// the same alias.
+type Aliases struct {
+	aliases    map[string]string
+	used       map[string]bool
+	reserved   map[string]bool
+	ignoreCase bool
+}
+
+// New returns the aliases for a format whose keywords, compared in any case,
+// cannot be identifiers
+func New(reserved ...string) *Aliases {
+	aliases := &Aliases{
+		aliases:  make(map[string]string),
+		used:     make(map[string]bool),
+		reserved: make(map[string]bool, len(reserved)),
+	}
+	for _, keyword := range reserved {
+		aliases.reserved[strings.ToLower(keyword)] = true
+	}
+	return aliases
+}
+
+// NewIgnoringCase returns the aliases for a format whose identifiers are
+// case insensitive: IDs that only differ in case get different aliases
+func NewIgnoringCase(reserved ...string) *Aliases {
+	aliases := New(reserved...)
+	aliases.ignoreCase = true
+	return aliases
+}
+
+func (aliases *Aliases) Get(id string) string {
+	if alias, exists := aliases.aliases[id]; exists {
+		return alias
+	}
+
+	alias := sanitise(id)
+	if alias != id || aliases.isTaken(alias) {
+		base := alias
+		for i := 1; aliases.isTaken(alias); i++ {
+			alias = fmt.Sprintf("%s_%d", base, i)
+		}
+	}
+
+	aliases.aliases[id] = alias
+	aliases.used[aliases.fold(alias)] = true
+	return alias
+}
+
+// Unique returns a new alias based on name, different from every alias
+// returned so far and from the ones returned later for any ID
+func (aliases *Aliases) Unique(name string) string {
+	alias := sanitise(name)
+	base := alias
+	for i := 1; aliases.isTaken(alias); i++ {
+		alias = fmt.Sprintf("%s_%d", base, i)
+	}
+
+	aliases.used[aliases.fold(alias)] = true
+	return alias
+}
+
+// IsAliased reports whether the alias of id is not id itself
+func (aliases *Aliases) IsAliased(id string) bool {
+	return aliases.Get(id) != id
+}
+
+func (aliases *Aliases) isTaken(alias string) bool {
+	return aliases.used[aliases.fold(alias)] || aliases.reserved[strings.ToLower(alias)]
+}
+
+func (aliases *Aliases) fold(alias string) string {
+	if aliases.ignoreCase {
+		return strings.ToLower(alias)
+	}
+	return alias
+}
+
+// IsIdentifier reports whether id is made of ASCII letters, digits and
+// underscores and does not start with a digit
+func IsIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+	for i, char := range id {
+		if !(char == '_' || isLetter(char) || (i > 0 && isDigit(char))) {
+			return false
+		}
+	}
+	return true
+}
+
+func sanitise(id string) string {
+	if IsIdentifier(id) {
+		return id
+	}
+
+	var builder strings.Builder
+	for _, char := range id {
+		if char == '_' || isLetter(char) || isDigit(char) {
+			builder.WriteRune(char)
+		} else {
+			builder.WriteRune('_')
+		}
+	}
+
+	sanitised := builder.String()
+	if sanitised == "" || isDigit(rune(sanitised[0])) {
+		sanitised = "n" + sanitised
+	}
+	return sanitised
+}
+
+func isLetter(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
+}
@@ -0,0 +1,47 @@
+package alias
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	aliases := New("end", "graph")
+
+	expected := [][2]string{
+		{"a", "a"},
+		{"a b", "a_b"},
+		{"a-b", "a_b_1"},
+		{"a_b", "a_b_2"},
+		{"1st", "n1st"},
+		{"", "n"},
+		{"End", "End_1"},
+		{"città", "citt_"},
+		{"a b", "a_b"},
+	}
+
+	for _, pair := range expected {
+		if alias := aliases.Get(pair[0]); alias != pair[1] {
+			t.Errorf("Expected alias %s for '%s', got %s", pair[1], pair[0], alias)
+		}
+	}
+
+	if unique := aliases.Unique("a"); unique != "a_1" {
+		t.Errorf("Expected unique alias a_1, got %s", unique)
+	}
+	if alias := aliases.Get("a_1"); alias != "a_1_1" {
+		t.Errorf("Expected alias a_1_1 for 'a_1', got %s", alias)
+	}
+
+	if aliases.IsAliased("a") || !aliases.IsAliased("a b") {
+		t.Errorf("Expected only 'a b' to be aliased")
+	}
+}
+
+func TestGetIgnoringCase(t *testing.T) {
+	aliases := NewIgnoringCase("label")
+
+	expected := [][2]string{{"a", "a"}, {"A", "A_1"}, {"Label", "Label_1"}, {"A", "A_1"}}
+	for _, pair := range expected {
+		if alias := aliases.Get(pair[0]); alias != pair[1] {
+			t.Errorf("Expected alias %s for '%s', got %s", pair[1], pair[0], alias)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package attribute
+
+import (
+	. "dot-parser/result"
+	"strings"
+)
+
+type ArrowSide uint8
+
+const (
+	BOTH_SIDES ArrowSide = iota
+	LEFT_SIDE
+	RIGHT_SIDE
+)
+
+// Arrow is a single arrowhead shape; an arrowType is a sequence of up to four
+// of them, drawn from the node outwards
+type Arrow struct {
+	Shape string
+	Open  bool
+	Side  ArrowSide
+}
+
+var arrowShapes = []string{"box", "crow", "curve", "icurve", "diamond", "dot", "inv", "none", "normal", "tee", "vee"}
+
+// deprecated arrow names and their modern spelling
+var arrowSynonyms = map[string]Arrow{
+	"ediamond": {Shape: "diamond", Open: true},
+	"open":     {Shape: "vee"},
+	"halfopen": {Shape: "vee", Side: LEFT_SIDE},
+	"empty":    {Shape: "normal", Open: true},
+	"invempty": {Shape: "inv", Open: true},
+}
+
+const maxArrows = 4
+
+// ParseArrowType reads an arrowType such as "normal", "odot" or
+// "lteeoldiamond" (= "ltee" followed by "oldiamond")
+func ParseArrowType(value string) Result[[]Arrow] {
+	var arrows []Arrow
+	rest := value
+	for rest != "" {
+		arrow, remaining, matched := parseArrow(rest)
+		if !matched || len(arrows) == maxArrows {
+			return makeValueError[[]Arrow](ARROW_TYPE, value)
+		}
+		arrows, rest = append(arrows, arrow), remaining
+	}
+
+	if len(arrows) == 0 {
+		return makeValueError[[]Arrow](ARROW_TYPE, value)
+	}
+	return Ok(arrows)
+}
+
+func parseArrow(value string) (Arrow, string, bool) {
+	for name, arrow := range arrowSynonyms {
+		if strings.HasPrefix(value, name) {
+			return arrow, value[len(name):], true
+		}
+	}
+
+	var arrow Arrow
+	if strings.HasPrefix(value, "o") {
+		arrow.Open, value = true, value[1:]
+	}
+	if strings.HasPrefix(value, "l") {
+		arrow.Side, value = LEFT_SIDE, value[1:]
+	} else if strings.HasPrefix(value, "r") {
+		arrow.Side, value = RIGHT_SIDE, value[1:]
+	}
+
+	for _, shape := range arrowShapes {
+		if strings.HasPrefix(value, shape) {
+			arrow.Shape = shape
+			return arrow, value[len(shape):], true
+		}
+	}
+	return arrow, value, false
+}
+
+func FormatArrowType(arrows []Arrow) string {
+	var out_string string
+	for _, arrow := range arrows {
+		if arrow.Open {
+			out_string += "o"
+		}
+		switch arrow.Side {
+		case LEFT_SIDE:
+			out_string += "l"
+		case RIGHT_SIDE:
+			out_string += "r"
+		}
+		out_string += arrow.Shape
+	}
+	return out_string
+}
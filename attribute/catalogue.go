@@ -0,0 +1,307 @@
+package attribute
+
+import "dot-parser/option"
+
+// Scope is the set of graph components an attribute can be attached to
+type Scope uint8
+
+const (
+	GRAPH_SCOPE Scope = 1 << iota
+	SUBGRAPH_SCOPE
+	CLUSTER_SCOPE
+	NODE_SCOPE
+	EDGE_SCOPE
+)
+
+func (scope Scope) Contains(other Scope) bool {
+	return scope&other == other
+}
+
+func (scope Scope) String() string {
+	var out_string string
+	names := []string{"graph", "subgraph", "cluster", "node", "edge"}
+	for i, name := range names {
+		if scope&(1<<i) != 0 {
+			if out_string != "" {
+				out_string += "|"
+			}
+			out_string += name
+		}
+	}
+	return out_string
+}
+
+type ValueType uint8
+
+const (
+	STRING ValueType = iota
+	ESC_STRING
+	LBL_STRING
+	INT
+	DOUBLE
+	BOOL
+	COLOR
+	COLOR_LIST
+	POINT
+	POINT_LIST
+	RECT
+	ARROW_TYPE
+	STYLE
+	SPLINE_TYPE
+	ADD_DOUBLE
+	ADD_POINT
+	DOUBLE_LIST
+	LAYER_LIST
+	LAYER_RANGE
+	PORT_POS
+	VIEW_PORT
+	PACK_MODE
+	START_TYPE
+	SHAPE
+	DIR_TYPE
+	RANK_DIR
+	RANK_TYPE
+	OUTPUT_MODE
+	PAGE_DIR
+	SMOOTH_TYPE
+	QUAD_TYPE
+	ORDERING
+	LABEL_JUST
+	LABEL_LOC
+	MODE
+	MODEL
+	SPLINES
+)
+
+func (valueType ValueType) String() string {
+	names := [...]string{
+		"string", "escString", "lblString", "int", "double", "bool", "color", "colorList",
+		"point", "pointList", "rect", "arrowType", "style", "splineType", "addDouble",
+		"addPoint", "doubleList", "layerList", "layerRange", "portPos", "viewPort",
+		"packMode", "startType", "shape", "dirType", "rankdir", "rankType", "outputMode",
+		"pagedir", "smoothType", "quadType", "ordering", "labeljust", "labelloc", "mode",
+		"model", "splines",
+	}
+	return names[valueType]
+}
+
+// Attribute describes a standard Graphviz attribute. An attribute value is
+// valid when it can be read as any of its Types.
+type Attribute struct {
+	Name    string
+	UsedBy  Scope
+	Types   []ValueType
+	Default string
+}
+
+const (
+	g = GRAPH_SCOPE
+	s = SUBGRAPH_SCOPE
+	c = CLUSTER_SCOPE
+	n = NODE_SCOPE
+	e = EDGE_SCOPE
+)
+
+func types(valueTypes ...ValueType) []ValueType {
+	return valueTypes
+}
+
+var catalogue = []Attribute{
+	{"_background", g, types(STRING), ""},
+	{"area", n | c, types(DOUBLE), "1.0"},
+	{"arrowhead", e, types(ARROW_TYPE), "normal"},
+	{"arrowsize", e, types(DOUBLE), "1.0"},
+	{"arrowtail", e, types(ARROW_TYPE), "normal"},
+	{"bb", g | c, types(RECT), ""},
+	{"beautify", g, types(BOOL), "false"},
+	{"bgcolor", g | c, types(COLOR, COLOR_LIST), ""},
+	{"center", g, types(BOOL), "false"},
+	{"charset", g, types(STRING), "UTF-8"},
+	{"class", g | s | c | n | e, types(STRING), ""},
+	{"cluster", s | c, types(BOOL), "false"},
+	{"color", c | n | e, types(COLOR, COLOR_LIST), "black"},
+	{"colorscheme", g | s | c | n | e, types(STRING), ""},
+	{"comment", g | n | e, types(STRING), ""},
+	{"compound", g, types(BOOL), "false"},
+	{"concentrate", g, types(BOOL), "false"},
+	{"constraint", e, types(BOOL), "true"},
+	{"Damping", g, types(DOUBLE), "0.99"},
+	{"decorate", e, types(BOOL), "false"},
+	{"defaultdist", g, types(DOUBLE), ""},
+	{"dim", g, types(INT), "2"},
+	{"dimen", g, types(INT), "2"},
+	{"dir", e, types(DIR_TYPE), "forward"},
+	{"diredgeconstraints", g, types(BOOL, STRING), "false"},
+	{"distortion", n, types(DOUBLE), "0.0"},
+	{"dpi", g, types(DOUBLE), "96.0"},
+	{"edgehref", e, types(ESC_STRING), ""},
+	{"edgetarget", e, types(ESC_STRING), ""},
+	{"edgetooltip", e, types(ESC_STRING), ""},
+	{"edgeURL", e, types(ESC_STRING), ""},
+	{"epsilon", g, types(DOUBLE), ""},
+	{"esep", g, types(ADD_DOUBLE, ADD_POINT), "+3"},
+	{"fillcolor", c | n | e, types(COLOR, COLOR_LIST), "lightgrey"},
+	{"fixedsize", n, types(BOOL, STRING), "false"},
+	{"fontcolor", g | c | n | e, types(COLOR), "black"},
+	{"fontname", g | c | n | e, types(STRING), "Times-Roman"},
+	{"fontnames", g, types(STRING), ""},
+	{"fontpath", g, types(STRING), ""},
+	{"fontsize", g | c | n | e, types(DOUBLE), "14.0"},
+	{"forcelabels", g, types(BOOL), "true"},
+	{"gradientangle", g | c | n, types(INT), ""},
+	{"group", n, types(STRING), ""},
+	{"head_lp", e, types(POINT), ""},
+	{"headclip", e, types(BOOL), "true"},
+	{"headhref", e, types(ESC_STRING), ""},
+	{"headlabel", e, types(LBL_STRING), ""},
+	{"headport", e, types(PORT_POS), "center"},
+	{"headtarget", e, types(ESC_STRING), ""},
+	{"headtooltip", e, types(ESC_STRING), ""},
+	{"headURL", e, types(ESC_STRING), ""},
+	{"height", n, types(DOUBLE), "0.5"},
+	{"href", g | c | n | e, types(ESC_STRING), ""},
+	{"id", g | c | n | e, types(ESC_STRING), ""},
+	{"image", n, types(STRING), ""},
+	{"imagepath", g, types(STRING), ""},
+	{"imagepos", n, types(STRING), "mc"},
+	{"imagescale", n, types(BOOL, STRING), "false"},
+	{"inputscale", g, types(DOUBLE), ""},
+	{"K", g | c, types(DOUBLE), "0.3"},
+	{"label", g | c | n | e, types(LBL_STRING), "\\N"},
+	{"label_scheme", g, types(INT), "0"},
+	{"labelangle", e, types(DOUBLE), "-25.0"},
+	{"labeldistance", e, types(DOUBLE), "1.0"},
+	{"labelfloat", e, types(BOOL), "false"},
+	{"labelfontcolor", e, types(COLOR), "black"},
+	{"labelfontname", e, types(STRING), "Times-Roman"},
+	{"labelfontsize", e, types(DOUBLE), "14.0"},
+	{"labelhref", e, types(ESC_STRING), ""},
+	{"labeljust", g | c, types(LABEL_JUST), "c"},
+	{"labelloc", g | c | n, types(LABEL_LOC), "t"},
+	{"labeltarget", e, types(ESC_STRING), ""},
+	{"labeltooltip", e, types(ESC_STRING), ""},
+	{"labelURL", e, types(ESC_STRING), ""},
+	{"landscape", g, types(BOOL), "false"},
+	{"layer", c | n | e, types(LAYER_RANGE), ""},
+	{"layerlistsep", g, types(STRING), ","},
+	{"layers", g, types(LAYER_LIST), ""},
+	{"layerselect", g, types(LAYER_RANGE), ""},
+	{"layersep", g, types(STRING), ":\t "},
+	{"layout", g, types(STRING), ""},
+	{"len", e, types(DOUBLE), "1.0"},
+	{"levels", g, types(INT), ""},
+	{"levelsgap", g, types(DOUBLE), "0.0"},
+	{"lhead", e, types(STRING), ""},
+	{"lheight", g | c, types(DOUBLE), ""},
+	{"linelength", g, types(INT), "128"},
+	{"lp", g | c | e, types(POINT), ""},
+	{"ltail", e, types(STRING), ""},
+	{"lwidth", g | c, types(DOUBLE), ""},
+	{"margin", g | c | n, types(DOUBLE, POINT), ""},
+	{"maxiter", g, types(INT), ""},
+	{"mclimit", g, types(DOUBLE), "1.0"},
+	{"mindist", g, types(DOUBLE), "1.0"},
+	{"minlen", e, types(INT), "1"},
+	{"mode", g, types(MODE), "major"},
+	{"model", g, types(MODEL), "shortpath"},
+	{"newrank", g, types(BOOL), "false"},
+	{"nodesep", g, types(DOUBLE), "0.25"},
+	{"nojustify", g | c | n | e, types(BOOL), "false"},
+	{"normalize", g, types(DOUBLE, BOOL), "false"},
+	{"notranslate", g, types(BOOL), "false"},
+	{"nslimit", g, types(DOUBLE), ""},
+	{"nslimit1", g, types(DOUBLE), ""},
+	{"oneblock", g, types(BOOL), "false"},
+	{"ordering", g | n, types(ORDERING), ""},
+	{"orientation", g | n, types(DOUBLE, STRING), ""},
+	{"outputorder", g, types(OUTPUT_MODE), "breadthfirst"},
+	{"overlap", g, types(BOOL, STRING), "true"},
+	{"overlap_scaling", g, types(DOUBLE), "-4"},
+	{"overlap_shrink", g, types(BOOL), "true"},
+	{"pack", g, types(BOOL, INT), "false"},
+	{"packmode", g, types(PACK_MODE), "node"},
+	{"pad", g, types(DOUBLE, POINT), "0.0555"},
+	{"page", g, types(DOUBLE, POINT), ""},
+	{"pagedir", g, types(PAGE_DIR), "BL"},
+	{"pencolor", c, types(COLOR), "black"},
+	{"penwidth", c | n | e, types(DOUBLE), "1.0"},
+	{"peripheries", c | n, types(INT), ""},
+	{"pin", n, types(BOOL), "false"},
+	{"pos", n | e, types(POINT, SPLINE_TYPE), ""},
+	{"quadtree", g, types(QUAD_TYPE, BOOL), "normal"},
+	{"quantum", g, types(DOUBLE), "0.0"},
+	{"rank", s | c, types(RANK_TYPE), ""},
+	{"rankdir", g, types(RANK_DIR), "TB"},
+	{"ranksep", g, types(DOUBLE, DOUBLE_LIST), "0.5"},
+	{"ratio", g, types(DOUBLE, STRING), ""},
+	{"rects", n, types(RECT), ""},
+	{"regular", n, types(BOOL), "false"},
+	{"remincross", g, types(BOOL), "true"},
+	{"repulsiveforce", g, types(DOUBLE), "1.0"},
+	{"resolution", g, types(DOUBLE), "96.0"},
+	{"root", g | n, types(STRING, BOOL), ""},
+	{"rotate", g, types(INT), "0"},
+	{"rotation", g, types(DOUBLE), "0"},
+	{"samehead", e, types(STRING), ""},
+	{"sametail", e, types(STRING), ""},
+	{"samplepoints", n, types(INT), "8"},
+	{"scale", g, types(DOUBLE, POINT), ""},
+	{"searchsize", g, types(INT), "30"},
+	{"sep", g, types(ADD_DOUBLE, ADD_POINT), "+4"},
+	{"shape", n, types(SHAPE), "ellipse"},
+	{"shapefile", n, types(STRING), ""},
+	{"showboxes", g | n | e, types(INT), "0"},
+	{"sides", n, types(INT), "4"},
+	{"size", g, types(DOUBLE, POINT), ""},
+	{"skew", n, types(DOUBLE), "0.0"},
+	{"smoothing", g, types(SMOOTH_TYPE), "none"},
+	{"sortv", g | c | n, types(INT), "0"},
+	{"splines", g, types(BOOL, SPLINES), ""},
+	{"start", g, types(START_TYPE), ""},
+	{"style", g | c | n | e, types(STYLE), ""},
+	{"stylesheet", g, types(STRING), ""},
+	{"tail_lp", e, types(POINT), ""},
+	{"tailclip", e, types(BOOL), "true"},
+	{"tailhref", e, types(ESC_STRING), ""},
+	{"taillabel", e, types(LBL_STRING), ""},
+	{"tailport", e, types(PORT_POS), "center"},
+	{"tailtarget", e, types(ESC_STRING), ""},
+	{"tailtooltip", e, types(ESC_STRING), ""},
+	{"tailURL", e, types(ESC_STRING), ""},
+	{"target", g | c | n | e, types(ESC_STRING), ""},
+	{"TBbalance", g, types(STRING), ""},
+	{"tooltip", g | c | n | e, types(ESC_STRING), ""},
+	{"truecolor", g, types(BOOL), ""},
+	{"URL", g | c | n | e, types(ESC_STRING), ""},
+	{"vertices", n, types(POINT_LIST), ""},
+	{"viewport", g, types(VIEW_PORT), ""},
+	{"voro_margin", g, types(DOUBLE), "0.05"},
+	{"weight", e, types(INT, DOUBLE), "1"},
+	{"width", n, types(DOUBLE), "0.75"},
+	{"xdotversion", g, types(STRING), ""},
+	{"xlabel", n | e, types(LBL_STRING), ""},
+	{"xlp", n | e, types(POINT), ""},
+	{"z", n, types(DOUBLE), "0.0"},
+}
+
+var attributesByName = func() map[string]Attribute {
+	attributes := make(map[string]Attribute, len(catalogue))
+	for _, attribute := range catalogue {
+		attributes[attribute.Name] = attribute
+	}
+	return attributes
+}()
+
+// Lookup returns the standard attribute with the given (case-sensitive) name
+func Lookup(name string) option.Option[Attribute] {
+	if attribute, exists := attributesByName[name]; exists {
+		return option.Some(attribute)
+	} else {
+		return option.None[Attribute]()
+	}
+}
+
+// Catalogue returns every standard attribute, sorted case-insensitively by name
+func Catalogue() []Attribute {
+	return append([]Attribute(nil), catalogue...)
+}
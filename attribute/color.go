@@ -0,0 +1,242 @@
+package attribute
+
+import (
+	"dot-parser/option"
+	. "dot-parser/result"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Color struct {
+	R uint8
+	G uint8
+	B uint8
+	A uint8
+}
+
+type WeightedColor struct {
+	Color    Color
+	Fraction option.Option[float64]
+}
+
+var transparent = Color{R: 255, G: 255, B: 254, A: 0}
+
+// ParseColor reads a color using the default (X11) colour scheme
+func ParseColor(value string) Result[Color] {
+	return ParseColorInScheme(value, "")
+}
+
+// ParseColorInScheme reads a color as "#rrggbb", "#rrggbbaa", "H,S,V" (or
+// "H S V") with components in [0,1], "/scheme/name", or a name looked up in
+// the given scheme. Only the "x11" and "svg" schemes are known; the empty
+// scheme means "x11".
+func ParseColorInScheme(value string, scheme string) Result[Color] {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return makeValueError[Color](COLOR, value)
+	}
+
+	switch value[0] {
+	case '#':
+		return parseHexColor(value)
+	case '/':
+		parts := strings.SplitN(value[1:], "/", 2)
+		if len(parts) != 2 {
+			return makeValueError[Color](COLOR, value)
+		}
+		return lookupColor(parts[1], parts[0], value)
+	}
+
+	if isHSVStart(value[0]) {
+		return parseHSVColor(value)
+	}
+
+	return lookupColor(value, scheme, value)
+}
+
+func FormatColor(color Color) string {
+	if color.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", color.R, color.G, color.B, color.A)
+}
+
+// ParseColorList reads a ':' separated list of colors, each optionally
+// followed by ";fraction", using the default (X11) colour scheme
+func ParseColorList(value string) Result[[]WeightedColor] {
+	return ParseColorListInScheme(value, "")
+}
+
+// ParseColorListInScheme reads a color list, looking up color names in the
+// given scheme as ParseColorInScheme does
+func ParseColorListInScheme(value string, scheme string) Result[[]WeightedColor] {
+	return parseColorList(value, func(name string) Result[Color] {
+		return ParseColorInScheme(name, scheme)
+	})
+}
+
+func parseColorList(value string, parseColor func(string) Result[Color]) Result[[]WeightedColor] {
+	var colors []WeightedColor
+	var total float64
+	for _, item := range strings.Split(value, ":") {
+		color := WeightedColor{Fraction: option.None[float64]()}
+		name := item
+		if idx := strings.LastIndex(item, ";"); idx >= 0 {
+			parsed := ParseDouble(item[idx+1:])
+			if parsed.IsErr() || parsed.Unwrap() < 0 || parsed.Unwrap() > 1 {
+				return makeValueError[[]WeightedColor](COLOR_LIST, value)
+			}
+			fraction := parsed.Unwrap()
+			name, color.Fraction, total = item[:idx], option.Some(fraction), total+fraction
+		}
+
+		parsed := parseColor(name)
+		if parsed.IsErr() || total > 1+1e-9 {
+			return makeValueError[[]WeightedColor](COLOR_LIST, value)
+		}
+		color.Color = parsed.Unwrap()
+		colors = append(colors, color)
+	}
+	return Ok(colors)
+}
+
+func FormatColorList(colors []WeightedColor) string {
+	items := make([]string, len(colors))
+	for i, color := range colors {
+		items[i] = FormatColor(color.Color)
+		if color.Fraction.IsSome() {
+			items[i] += ";" + FormatDouble(color.Fraction.Unwrap())
+		}
+	}
+	return strings.Join(items, ":")
+}
+
+func parseHexColor(value string) Result[Color] {
+	digits := value[1:]
+	if len(digits) != 6 && len(digits) != 8 {
+		return makeValueError[Color](COLOR, value)
+	}
+
+	components := []uint8{0, 0, 0, 255}
+	for i := 0; i < len(digits); i += 2 {
+		component, err := strconv.ParseUint(digits[i:i+2], 16, 8)
+		if err != nil {
+			return makeValueError[Color](COLOR, value)
+		}
+		components[i/2] = uint8(component)
+	}
+	return Ok(Color{R: components[0], G: components[1], B: components[2], A: components[3]})
+}
+
+func isHSVStart(char byte) bool {
+	return char == '.' || (char >= '0' && char <= '9')
+}
+
+func parseHSVColor(value string) Result[Color] {
+	fields := strings.FieldsFunc(value, func(char rune) bool {
+		return char == ',' || char == ' ' || char == '\t'
+	})
+	if len(fields) != 3 {
+		return makeValueError[Color](COLOR, value)
+	}
+
+	var hsv [3]float64
+	for i, field := range fields {
+		component := ParseDouble(field)
+		if component.IsErr() {
+			return makeValueError[Color](COLOR, value)
+		}
+		hsv[i] = math.Max(0, math.Min(1, component.Unwrap()))
+	}
+	return Ok(HSVToColor(hsv[0], hsv[1], hsv[2]))
+}
+
+// HSVToColor converts hue, saturation and value in [0,1] to an opaque color
+func HSVToColor(h float64, s float64, v float64) Color {
+	if s <= 0 {
+		gray := uint8(math.Round(v * 255))
+		return Color{R: gray, G: gray, B: gray, A: 255}
+	}
+
+	h = math.Mod(h, 1) * 6
+	sector := math.Floor(h)
+	f := h - sector
+	p, q, t := v*(1-s), v*(1-s*f), v*(1-s*(1-f))
+
+	var r, g, b float64
+	switch int(sector) {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+
+	return Color{
+		R: uint8(math.Round(r * 255)),
+		G: uint8(math.Round(g * 255)),
+		B: uint8(math.Round(b * 255)),
+		A: 255,
+	}
+}
+
+// isKnownScheme reports whether the color names of a scheme can be looked up
+func isKnownScheme(scheme string) bool {
+	switch strings.ToLower(scheme) {
+	case "", "x11", "svg":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkColor reads a color as ParseColorInScheme does, but accepts any name
+// of a scheme that is not known, such as the Brewer ones, as the zero color
+func checkColor(value string, scheme string) Result[Color] {
+	res := ParseColorInScheme(value, scheme)
+	if res.IsOk() {
+		return res
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "/") {
+		if parts := strings.SplitN(value[1:], "/", 2); len(parts) == 2 && parts[1] != "" && !isKnownScheme(parts[0]) {
+			return Ok(Color{})
+		}
+	} else if value != "" && value[0] != '#' && !isKnownScheme(scheme) {
+		return Ok(Color{})
+	}
+	return res
+}
+
+func lookupColor(name string, scheme string, value string) Result[Color] {
+	name = strings.ToLower(strings.ReplaceAll(name, " ", ""))
+	switch name {
+	case "transparent", "invis", "none":
+		return Ok(transparent)
+	}
+
+	var colors map[string]Color
+	switch strings.ToLower(scheme) {
+	case "", "x11":
+		colors = x11Colors
+	case "svg":
+		colors = svgColors
+	default:
+		return makeValueError[Color](COLOR, value)
+	}
+
+	if color, exists := colors[name]; exists {
+		return Ok(color)
+	}
+	return makeValueError[Color](COLOR, value)
+}
@@ -0,0 +1,80 @@
+package attribute
+
+import "strings"
+
+// EscContext holds the names substituted when expanding an escString
+type EscContext struct {
+	Graph    string
+	Node     string
+	Tail     string
+	Head     string
+	Label    string
+	IsDirect bool
+}
+
+// ExpandEscString replaces \G, \N, \E, \T, \H and \L with the graph name,
+// node name, edge name ("tail->head" or "tail--head"), tail name, head name
+// and object label. Every other escape sequence is left untouched.
+func ExpandEscString(value string, ctx EscContext) string {
+	var builder strings.Builder
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' || i+1 == len(runes) {
+			builder.WriteRune(runes[i])
+			continue
+		}
+
+		i += 1
+		switch runes[i] {
+		case 'G':
+			builder.WriteString(ctx.Graph)
+		case 'N':
+			builder.WriteString(ctx.Node)
+		case 'E':
+			arc := "--"
+			if ctx.IsDirect {
+				arc = "->"
+			}
+			builder.WriteString(ctx.Tail + arc + ctx.Head)
+		case 'T':
+			builder.WriteString(ctx.Tail)
+		case 'H':
+			builder.WriteString(ctx.Head)
+		case 'L':
+			builder.WriteString(ctx.Label)
+		default:
+			builder.WriteRune('\\')
+			builder.WriteRune(runes[i])
+		}
+	}
+	return builder.String()
+}
+
+// SplitLines splits an expanded escString into its lines, ending a line at
+// each \n, \l and \r escape. A trailing line break does not start an empty
+// line. Other escape sequences are replaced by the escaped character.
+func SplitLines(value string) []string {
+	var lines []string
+	var builder strings.Builder
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' || i+1 == len(runes) {
+			builder.WriteRune(runes[i])
+			continue
+		}
+
+		i += 1
+		switch runes[i] {
+		case 'n', 'l', 'r':
+			lines = append(lines, builder.String())
+			builder.Reset()
+		default:
+			builder.WriteRune(runes[i])
+		}
+	}
+
+	if builder.Len() > 0 || len(lines) == 0 {
+		lines = append(lines, builder.String())
+	}
+	return lines
+}
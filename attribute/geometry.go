@@ -0,0 +1,150 @@
+package attribute
+
+import (
+	"dot-parser/option"
+	. "dot-parser/result"
+	"strings"
+)
+
+type Point struct {
+	X     float64
+	Y     float64
+	Z     option.Option[float64]
+	Fixed bool
+}
+
+type Rect struct {
+	LLX float64
+	LLY float64
+	URX float64
+	URY float64
+}
+
+// Spline is a piecewise Bézier curve: Points holds 3n+1 control points and the
+// optional Start and End are the positions of the arrowheads
+type Spline struct {
+	Start  option.Option[Point]
+	End    option.Option[Point]
+	Points []Point
+}
+
+// ParsePoint reads "x,y", "x,y,z", optionally followed by '!' to mark the
+// position as fixed
+func ParsePoint(value string) Result[Point] {
+	value = strings.TrimSpace(value)
+	fixed := strings.HasSuffix(value, "!")
+	numbers, valid := parseDoubles(strings.TrimSuffix(value, "!"), ",", 2, 3)
+	if !valid {
+		return makeValueError[Point](POINT, value)
+	}
+
+	point := Point{X: numbers[0], Y: numbers[1], Z: option.None[float64](), Fixed: fixed}
+	if len(numbers) == 3 {
+		point.Z = option.Some(numbers[2])
+	}
+	return Ok(point)
+}
+
+func FormatPoint(point Point) string {
+	out_string := FormatDouble(point.X) + "," + FormatDouble(point.Y)
+	if point.Z.IsSome() {
+		out_string += "," + FormatDouble(point.Z.Unwrap())
+	}
+	if point.Fixed {
+		out_string += "!"
+	}
+	return out_string
+}
+
+// ParsePointList reads a whitespace separated list of points
+func ParsePointList(value string) Result[[]Point] {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return makeValueError[[]Point](POINT_LIST, value)
+	}
+
+	points := make([]Point, len(fields))
+	for i, field := range fields {
+		point := ParsePoint(field)
+		if point.IsErr() {
+			return makeValueError[[]Point](POINT_LIST, value)
+		}
+		points[i] = point.Unwrap()
+	}
+	return Ok(points)
+}
+
+func FormatPointList(points []Point) string {
+	items := make([]string, len(points))
+	for i, point := range points {
+		items[i] = FormatPoint(point)
+	}
+	return strings.Join(items, " ")
+}
+
+// ParseRect reads "llx,lly,urx,ury"
+func ParseRect(value string) Result[Rect] {
+	numbers, valid := parseDoubles(strings.TrimSpace(value), ",", 4, 4)
+	if !valid {
+		return makeValueError[Rect](RECT, value)
+	}
+	return Ok(Rect{LLX: numbers[0], LLY: numbers[1], URX: numbers[2], URY: numbers[3]})
+}
+
+func FormatRect(rect Rect) string {
+	return strings.Join([]string{
+		FormatDouble(rect.LLX),
+		FormatDouble(rect.LLY),
+		FormatDouble(rect.URX),
+		FormatDouble(rect.URY),
+	}, ",")
+}
+
+// ParseSplineType reads a ';' separated list of splines, each written as
+// ("e,x,y")? ("s,x,y")? point (point point point)+
+func ParseSplineType(value string) Result[[]Spline] {
+	var splines []Spline
+	for _, item := range strings.Split(value, ";") {
+		spline := Spline{Start: option.None[Point](), End: option.None[Point]()}
+		fields := strings.Fields(item)
+
+		for len(fields) > 0 && (strings.HasPrefix(fields[0], "e,") || strings.HasPrefix(fields[0], "s,")) {
+			point := ParsePoint(fields[0][2:])
+			if point.IsErr() {
+				return makeValueError[[]Spline](SPLINE_TYPE, value)
+			}
+			if fields[0][0] == 'e' {
+				spline.End = option.Some(point.Unwrap())
+			} else {
+				spline.Start = option.Some(point.Unwrap())
+			}
+			fields = fields[1:]
+		}
+
+		if len(fields) < 4 || len(fields)%3 != 1 {
+			return makeValueError[[]Spline](SPLINE_TYPE, value)
+		}
+		points := ParsePointList(strings.Join(fields, " "))
+		if points.IsErr() {
+			return makeValueError[[]Spline](SPLINE_TYPE, value)
+		}
+		spline.Points = points.Unwrap()
+		splines = append(splines, spline)
+	}
+	return Ok(splines)
+}
+
+func FormatSplineType(splines []Spline) string {
+	items := make([]string, len(splines))
+	for i, spline := range splines {
+		var fields []string
+		if spline.End.IsSome() {
+			fields = append(fields, "e,"+FormatPoint(spline.End.Unwrap()))
+		}
+		if spline.Start.IsSome() {
+			fields = append(fields, "s,"+FormatPoint(spline.Start.Unwrap()))
+		}
+		items[i] = strings.Join(append(fields, FormatPointList(spline.Points)), " ")
+	}
+	return strings.Join(items, ";")
+}
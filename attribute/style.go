@@ -0,0 +1,80 @@
+package attribute
+
+import (
+	. "dot-parser/result"
+	"strings"
+)
+
+// StyleItem is a single entry of a style list, e.g. "setlinewidth(2)" has
+// Name "setlinewidth" and Args ["2"]
+type StyleItem struct {
+	Name string
+	Args []string
+}
+
+// ParseStyle reads a ',' separated list of style items; commas inside the
+// parentheses of an item separate its arguments
+func ParseStyle(value string) Result[[]StyleItem] {
+	var items []StyleItem
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		end := strings.IndexAny(rest, ",(")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		item := StyleItem{Name: strings.TrimSpace(rest[:end])}
+		if item.Name == "" {
+			return makeValueError[[]StyleItem](STYLE, value)
+		}
+		rest = rest[end:]
+
+		if strings.HasPrefix(rest, "(") {
+			closing := strings.IndexByte(rest, ')')
+			if closing < 0 {
+				return makeValueError[[]StyleItem](STYLE, value)
+			}
+			for _, arg := range strings.Split(rest[1:closing], ",") {
+				item.Args = append(item.Args, strings.TrimSpace(arg))
+			}
+			rest = strings.TrimSpace(rest[closing+1:])
+		}
+
+		if rest != "" {
+			if rest[0] != ',' {
+				return makeValueError[[]StyleItem](STYLE, value)
+			}
+			rest = strings.TrimSpace(rest[1:])
+			if rest == "" {
+				return makeValueError[[]StyleItem](STYLE, value)
+			}
+		}
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return makeValueError[[]StyleItem](STYLE, value)
+	}
+	return Ok(items)
+}
+
+func FormatStyle(items []StyleItem) string {
+	formatted := make([]string, len(items))
+	for i, item := range items {
+		formatted[i] = item.Name
+		if len(item.Args) > 0 {
+			formatted[i] += "(" + strings.Join(item.Args, ",") + ")"
+		}
+	}
+	return strings.Join(formatted, ",")
+}
+
+// HasStyle reports whether the style list contains an item with the given name
+func HasStyle(items []StyleItem, name string) bool {
+	for _, item := range items {
+		if item.Name == name {
+			return true
+		}
+	}
+	return false
+}
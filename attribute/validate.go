@@ -0,0 +1,214 @@
+package attribute
+
+import (
+	. "dot-parser/lexer"
+	"dot-parser/option"
+	"dot-parser/parser"
+	"fmt"
+	"strings"
+)
+
+type DiagnosticKind uint8
+
+const (
+	UNKNOWN_ATTRIBUTE DiagnosticKind = iota
+	WRONG_SCOPE
+	INVALID_VALUE
+)
+
+type Diagnostic struct {
+	Kind       DiagnosticKind
+	Position   Position
+	Scope      Scope
+	Key        string
+	Value      string
+	Suggestion option.Option[string]
+}
+
+func (diag Diagnostic) Error() string {
+	var message string
+	switch diag.Kind {
+	case UNKNOWN_ATTRIBUTE:
+		message = fmt.Sprintf("unknown attribute \"%s\"", diag.Key)
+		if diag.Suggestion.IsSome() {
+			message += fmt.Sprintf(" (did you mean \"%s\"?)", diag.Suggestion.Unwrap())
+		}
+	case WRONG_SCOPE:
+		message = fmt.Sprintf("attribute \"%s\" cannot be used on %s", diag.Key, diag.Scope)
+	case INVALID_VALUE:
+		expected := option.Map(Lookup(diag.Key), func(attribute Attribute) string {
+			var names []string
+			for _, valueType := range attribute.Types {
+				names = append(names, valueType.String())
+			}
+			return strings.Join(names, " or ")
+		})
+		message = fmt.Sprintf("invalid value \"%s\" for attribute \"%s\", expected %s", diag.Value, diag.Key, expected.OrElse("?"))
+	}
+
+	return fmt.Sprintf(
+		"Attribute error at line %d column %d: %s",
+		diag.Position.Line(),
+		diag.Position.Column(),
+		message)
+}
+
+// Validate checks every attribute of the graph against the catalogue of
+// standard attributes. Diagnostics are returned in statement order. Colors
+// are looked up in the colorscheme in effect for their statement.
+func Validate(graph parser.Graph) []Diagnostic {
+	return validateStatements(graph.Statements, GRAPH_SCOPE, colorSchemes{})
+}
+
+// colorSchemes holds the colorscheme attributes in effect for the graph, the
+// nodes and the edges
+type colorSchemes struct {
+	graph string
+	node  string
+	edge  string
+}
+
+// schemeOf returns the colorscheme set by attribute maps, or the inherited
+// one when they set none
+func schemeOf(attributes []parser.AttributeMap, inherited string) string {
+	for _, attributeMap := range attributes {
+		if scheme, exists := attributeMap["colorscheme"]; exists {
+			inherited = scheme
+		}
+	}
+	return inherited
+}
+
+func validateStatements(stmts []parser.Statement, graphScope Scope, schemes colorSchemes) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *parser.Node:
+			diagnostics = append(diagnostics, validateMaps(stmt.Attributes, NODE_SCOPE, stmt.Position, schemeOf(stmt.Attributes, schemes.node))...)
+		case *parser.Edge:
+			diagnostics = append(diagnostics, validateMaps(stmt.Attributes, EDGE_SCOPE, stmt.Position, schemeOf(stmt.Attributes, schemes.edge))...)
+		case *parser.AttributeStmt:
+			scheme := &schemes.graph
+			switch stmt.Level {
+			case parser.NODE_LEVEL:
+				scheme = &schemes.node
+			case parser.EDGE_LEVEL:
+				scheme = &schemes.edge
+			}
+			*scheme = schemeOf(stmt.Attributes, *scheme)
+			diagnostics = append(diagnostics, validateMaps(stmt.Attributes, levelScope(stmt.Level, graphScope), stmt.Position, *scheme)...)
+		case *parser.SingleAttribute:
+			if stmt.Key == "colorscheme" {
+				schemes.graph = stmt.Value
+			}
+			diagnostics = append(diagnostics, validateAttribute(stmt.Key, stmt.Value, graphScope, stmt.Position, schemes.graph)...)
+		case *parser.Subgraph:
+			diagnostics = append(diagnostics, validateStatements(stmt.Statements, subgraphScope(stmt), schemes)...)
+		}
+	}
+	return diagnostics
+}
+
+// subgraphScope follows the Graphviz convention that subgraphs whose name
+// starts with "cluster" are clusters
+func subgraphScope(subgraph *parser.Subgraph) Scope {
+	if strings.HasPrefix(subgraph.Name.OrElse(""), "cluster") {
+		return CLUSTER_SCOPE
+	}
+	return SUBGRAPH_SCOPE
+}
+
+func levelScope(level parser.AttributeLevel, graphScope Scope) Scope {
+	switch level {
+	case parser.NODE_LEVEL:
+		return NODE_SCOPE
+	case parser.EDGE_LEVEL:
+		return EDGE_SCOPE
+	default:
+		return graphScope
+	}
+}
+
+func validateMaps(attributes []parser.AttributeMap, scope Scope, position Position, scheme string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, attributeMap := range attributes {
+		for _, key := range attributeMap.Keys() {
+			diagnostics = append(diagnostics, validateAttribute(key, attributeMap[key], scope, position, scheme)...)
+		}
+	}
+	return diagnostics
+}
+
+func validateAttribute(key string, value string, scope Scope, position Position, scheme string) []Diagnostic {
+	diag := Diagnostic{Position: position, Scope: scope, Key: key, Value: value}
+
+	lookup := Lookup(key)
+	if lookup.IsNone() {
+		diag.Kind = UNKNOWN_ATTRIBUTE
+		diag.Suggestion = suggest(key)
+		return []Diagnostic{diag}
+	}
+
+	attribute := lookup.Unwrap()
+	if !attribute.UsedBy.Contains(scope) {
+		diag.Kind = WRONG_SCOPE
+		return []Diagnostic{diag}
+	}
+
+	for _, valueType := range attribute.Types {
+		if IsValidInScheme(valueType, value, scheme) {
+			return nil
+		}
+	}
+	diag.Kind = INVALID_VALUE
+	return []Diagnostic{diag}
+}
+
+// suggest returns the standard attribute closest to name, if any is within
+// two edits of it
+func suggest(name string) option.Option[string] {
+	best := option.None[string]()
+	bestDistance := 3
+	for _, attribute := range catalogue {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(attribute.Name)); distance < bestDistance {
+			best, bestDistance = option.Some(attribute.Name), distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Damerau-Levenshtein distance restricted to adjacent transpositions
+func editDistance(first string, second string) int {
+	a, b := []rune(first), []rune(second)
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = minInt(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = minInt(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func minInt(first int, others ...int) int {
+	for _, other := range others {
+		if other < first {
+			first = other
+		}
+	}
+	return first
+}
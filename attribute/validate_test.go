@@ -0,0 +1,162 @@
+package attribute
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	shape := Lookup("shape")
+	if shape.IsNone() {
+		t.Fatalf("Expected attribute 'shape' to be in the catalogue")
+	}
+
+	if attr := shape.Unwrap(); attr.UsedBy != NODE_SCOPE || attr.Default != "ellipse" {
+		t.Fatalf("Expected node attribute 'shape' with default 'ellipse', got %v", attr)
+	}
+
+	if attr := Lookup("Shape"); attr.IsSome() {
+		t.Fatalf("Expected lookup to be case-sensitive, got %v", attr)
+	}
+}
+
+func TestValidateValidGraph(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		rankdir = LR
+		node [ shape = box, penwidth = 2 ]
+		a [ pos = "1,2!" ]
+		a -> b [ weight = 3, dir = back ]
+	}`)
+
+	if diagnostics := Validate(graph); len(diagnostics) != 0 {
+		t.Fatalf("Expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestValidateUnknownAttribute(t *testing.T) {
+	graph := testutil.ParseGraph(t, "digraph {\n a [ shpae = box ] \n}")
+
+	diagnostics := Validate(graph)
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic, got %v", diagnostics)
+	}
+
+	diag := diagnostics[0]
+	if diag.Kind != UNKNOWN_ATTRIBUTE || diag.Key != "shpae" || diag.Position.Line() != 2 {
+		t.Fatalf("Expected unknown attribute 'shpae' on line 2, got %v", diag)
+	}
+
+	if diag.Suggestion.IsNone() || diag.Suggestion.Unwrap() != "shape" {
+		t.Fatalf("Expected suggestion 'shape', got %v", diag.Suggestion)
+	}
+}
+
+func TestValidateWrongScope(t *testing.T) {
+	graph := testutil.ParseGraph(t, "graph { shape = box; edge [ rankdir = LR ] }")
+
+	diagnostics := Validate(graph)
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected two diagnostics, got %v", diagnostics)
+	}
+
+	for _, diag := range diagnostics {
+		if diag.Kind != WRONG_SCOPE {
+			t.Fatalf("Expected wrong scope diagnostic, got %s", diag.Error())
+		}
+	}
+
+	if diagnostics[0].Scope != GRAPH_SCOPE || diagnostics[1].Scope != EDGE_SCOPE {
+		t.Fatalf("Expected graph and edge scopes, got %v", diagnostics)
+	}
+}
+
+func TestValidateInvalidValue(t *testing.T) {
+	graph := testutil.ParseGraph(t, "digraph { a -> b [ penwidth = thick ]; c [ shape = blob ] }")
+
+	diagnostics := Validate(graph)
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected two diagnostics, got %v", diagnostics)
+	}
+
+	if diag := diagnostics[0]; diag.Kind != INVALID_VALUE || diag.Key != "penwidth" || diag.Value != "thick" {
+		t.Fatalf("Expected invalid value 'thick' for 'penwidth', got %s", diag.Error())
+	}
+
+	if diag := diagnostics[1]; diag.Kind != INVALID_VALUE || diag.Key != "shape" {
+		t.Fatalf("Expected invalid value for 'shape', got %s", diag.Error())
+	}
+}
+
+func TestValidateColorSchemes(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		a [ colorscheme = blues9, color = 3 ]
+		node [ colorscheme = svg ]
+		b [ color = crimson, fillcolor = "/accent3/1:/blues9/2" ]
+		edge [ colorscheme = oranges9 ]
+		a -> b [ color = 7 ]
+		c [ color = 3 ]
+	}`)
+
+	diagnostics := Validate(graph)
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected one diagnostic, got %v", diagnostics)
+	}
+
+	if diag := diagnostics[0]; diag.Kind != INVALID_VALUE || diag.Key != "color" || diag.Position.Line() != 7 {
+		t.Fatalf("Expected invalid color '3' in the svg scheme on line 7, got %s", diag.Error())
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	valid := map[ValueType][]string{
+		INT:       {"0", "-3"},
+		DOUBLE:    {"1", ".5", "-2.25", "2.", "1e3"},
+		BOOL:      {"true", "No", "0"},
+		POINT:     {"1,2", "1.5,2,3", "1,2!"},
+		RECT:      {"0,0,100,200"},
+		PACK_MODE: {"node", "array_tu4"},
+		RANK_DIR:  {"LR"},
+	}
+	invalid := map[ValueType][]string{
+		INT:       {"1.5", "x"},
+		DOUBLE:    {"thick", "nan", "inf", "-Infinity", "0x1p4", "1_000", "1e999"},
+		BOOL:      {"maybe"},
+		POINT:     {"1", "1,2,3,4"},
+		RECT:      {"0,0,100"},
+		PACK_MODE: {"cluster"},
+		RANK_DIR:  {"lr"},
+	}
+
+	for valueType, values := range valid {
+		for _, value := range values {
+			if !IsValid(valueType, value) {
+				t.Errorf("Expected '%s' to be a valid %s", value, valueType)
+			}
+		}
+	}
+
+	for valueType, values := range invalid {
+		for _, value := range values {
+			if IsValid(valueType, value) {
+				t.Errorf("Expected '%s' to be an invalid %s", value, valueType)
+			}
+		}
+	}
+}
+
+func TestValidateSubgraphScopes(t *testing.T) {
+	graph := testutil.ParseGraph(t, "digraph { rank = same; subgraph cluster_0 { pencolor = red } subgraph s { rank = same; pencolor = red } }")
+
+	diagnostics := Validate(graph)
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected two diagnostics, got %v", diagnostics)
+	}
+
+	if diag := diagnostics[0]; diag.Kind != WRONG_SCOPE || diag.Key != "rank" || diag.Scope != GRAPH_SCOPE {
+		t.Fatalf("Expected 'rank' to be rejected at graph scope, got %s", diag.Error())
+	}
+
+	if diag := diagnostics[1]; diag.Kind != WRONG_SCOPE || diag.Key != "pencolor" || diag.Scope != SUBGRAPH_SCOPE {
+		t.Fatalf("Expected 'pencolor' to be rejected at subgraph scope, got %s", diag.Error())
+	}
+}
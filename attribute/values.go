@@ -0,0 +1,312 @@
+package attribute
+
+import (
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var enumerations = map[ValueType][]string{
+	SHAPE: {
+		"box", "polygon", "ellipse", "oval", "circle", "point", "egg", "triangle",
+		"plaintext", "plain", "diamond", "trapezium", "parallelogram", "house",
+		"pentagon", "hexagon", "septagon", "octagon", "doublecircle", "doubleoctagon",
+		"tripleoctagon", "invtriangle", "invtrapezium", "invhouse", "Mdiamond",
+		"Msquare", "Mcircle", "rect", "rectangle", "square", "star", "none",
+		"underline", "cylinder", "note", "tab", "folder", "box3d", "component",
+		"promoter", "cds", "terminator", "utr", "primersite", "restrictionsite",
+		"fivepoverhang", "threepoverhang", "noverhang", "assembly", "signature",
+		"insulator", "ribosite", "rnastab", "proteasesite", "proteinstab",
+		"rpromoter", "rarrow", "larrow", "lpromoter", "record", "Mrecord",
+	},
+	DIR_TYPE:    {"forward", "back", "both", "none"},
+	RANK_DIR:    {"TB", "LR", "BT", "RL"},
+	RANK_TYPE:   {"same", "min", "source", "max", "sink"},
+	OUTPUT_MODE: {"breadthfirst", "nodesfirst", "edgesfirst"},
+	PAGE_DIR:    {"BL", "BR", "TL", "TR", "RB", "RT", "LB", "LT"},
+	SMOOTH_TYPE: {"none", "avg_dist", "graph_dist", "power_dist", "rng", "spring", "triangle"},
+	QUAD_TYPE:   {"normal", "fast", "none"},
+	ORDERING:    {"in", "out", ""},
+	LABEL_JUST:  {"l", "r", "c"},
+	LABEL_LOC:   {"t", "b", "c"},
+	MODE:        {"major", "KK", "hier", "ipsep", "spring", "maxent"},
+	MODEL:       {"circuit", "subset", "mds", "shortpath"},
+	SPLINES:     {"none", "", "line", "polyline", "curved", "ortho", "spline", "compound"},
+}
+
+var (
+	packModePattern  = regexp.MustCompile(`^(node|clust|graph|array(_[a-zA-Z]*)?[0-9]*)$`)
+	startTypePattern = regexp.MustCompile(`^(regular|self|random)?[0-9]*$`)
+	// doublePattern leaves out what strconv.ParseFloat reads besides decimal
+	// numbers: infinities, NaN, hexadecimal floats and underscores
+	doublePattern = regexp.MustCompile(`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+)
+
+type ValueError struct {
+	Type  ValueType
+	Value string
+}
+
+func (err *ValueError) Error() string {
+	return fmt.Sprintf("invalid %s \"%s\"", err.Type, err.Value)
+}
+
+func makeValueError[T any](valueType ValueType, value string) Result[T] {
+	return Err[T](&ValueError{Type: valueType, Value: value})
+}
+
+type AttributeError struct {
+	Attribute parser.SingleAttribute
+	Err       error
+}
+
+func (err *AttributeError) Error() string {
+	return fmt.Sprintf(
+		"Attribute error at line %d column %d: attribute \"%s\": %s",
+		err.Attribute.Position.Line(),
+		err.Attribute.Position.Column(),
+		err.Attribute.Key,
+		err.Err)
+}
+
+func (err *AttributeError) Unwrap() error {
+	return err.Err
+}
+
+// ParseAttribute reads the value of attr with one of the typed parsers of this
+// package, e.g. ParseAttribute(attr, ParseColor)
+func ParseAttribute[T any](attr parser.SingleAttribute, parse func(string) Result[T]) Result[T] {
+	res := parse(attr.Value)
+	if res.IsErr() {
+		return Err[T](&AttributeError{Attribute: attr, Err: res.UnwrapErr()})
+	}
+	return res
+}
+
+// FormatAttribute builds an attribute from a typed value, e.g.
+// FormatAttribute("color", color, FormatColor)
+func FormatAttribute[T any](key string, value T, format func(T) string) parser.SingleAttribute {
+	return parser.SingleAttribute{Key: key, Value: format(value)}
+}
+
+func ParseInt(value string) Result[int] {
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return makeValueError[int](INT, value)
+	}
+	return Ok(number)
+}
+
+func FormatInt(value int) string {
+	return strconv.Itoa(value)
+}
+
+// ParseDouble reads a finite decimal number
+func ParseDouble(value string) Result[float64] {
+	trimmed := strings.TrimSpace(value)
+	if !doublePattern.MatchString(trimmed) {
+		return makeValueError[float64](DOUBLE, value)
+	}
+	number, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return makeValueError[float64](DOUBLE, value)
+	}
+	return Ok(number)
+}
+
+func FormatDouble(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// FormatReal formats value like FormatDouble, with a decimal point even when
+// it is integral, for the formats that tell reals from integers by it
+func FormatReal(value float64) string {
+	formatted := FormatDouble(value)
+	if !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+// ParseBool accepts "true", "false", "yes" and "no" in any case, as well as
+// integers, which are true when non-zero
+func ParseBool(value string) Result[bool] {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes":
+		return Ok(true)
+	case "false", "no":
+		return Ok(false)
+	}
+
+	return FlatMap(ParseInt(value), func(number int) Result[bool] {
+		return Ok(number != 0)
+	})
+}
+
+func FormatBool(value bool) string {
+	return strconv.FormatBool(value)
+}
+
+// InferType returns INT when every value is an integer, DOUBLE when every
+// value is a finite number and STRING otherwise, or when there are no values
+func InferType(values []string) ValueType {
+	if len(values) == 0 {
+		return STRING
+	}
+
+	valueType := INT
+	for _, value := range values {
+		if ParseInt(value).IsOk() {
+			continue
+		}
+		if ParseDouble(value).IsErr() {
+			return STRING
+		}
+		valueType = DOUBLE
+	}
+	return valueType
+}
+
+// Column is an attribute of a set of nodes, edges or graphs as written by the
+// exporters whose formats declare typed attributes
+type Column struct {
+	Key string
+	// Name is the key renamed to fit the format
+	Name string
+	// Type is inferred from all the values of the attribute
+	Type ValueType
+}
+
+// InferColumns returns the columns of the attributes, sorted by key. The
+// name of a column is its key made valid by sanitize, or the key itself when
+// sanitize is nil, then prefixed with prefix as long as it is reserved or
+// named like another attribute.
+func InferColumns(attributes []parser.AttributeMap, sanitize func(string) string, reserved func(string) bool, prefix string) []Column {
+	// merged holds one value of each attribute, to list and look up the keys
+	merged := make(parser.AttributeMap)
+	values := make(map[string][]string)
+	for _, attributes := range attributes {
+		for key, value := range attributes {
+			merged[key] = value
+			values[key] = append(values[key], value)
+		}
+	}
+
+	used := make(map[string]bool)
+	columns := make([]Column, 0, len(merged))
+	for _, key := range merged.Keys() {
+		name := key
+		if sanitize != nil {
+			name = sanitize(key)
+		}
+		for {
+			_, isKey := merged[name]
+			if !reserved(name) && !used[name] && (name == key || !isKey) {
+				break
+			}
+			name = prefix + name
+		}
+
+		used[name] = true
+		columns = append(columns, Column{Key: key, Name: name, Type: InferType(values[key])})
+	}
+	return columns
+}
+
+func isOk[T any](res Result[T]) bool {
+	return res.IsOk()
+}
+
+// IsValid reports whether value can be read as the given value type
+func IsValid(valueType ValueType, value string) bool {
+	return IsValidInScheme(valueType, value, "")
+}
+
+// IsValidInScheme reports whether value can be read as the given value type,
+// looking up color names in the given color scheme. Names of schemes that
+// are not known, such as the Brewer ones, are not checked.
+func IsValidInScheme(valueType ValueType, value string, scheme string) bool {
+	switch valueType {
+	case INT:
+		return isOk(ParseInt(value))
+	case DOUBLE:
+		return isOk(ParseDouble(value))
+	case BOOL:
+		return isOk(ParseBool(value))
+	case COLOR:
+		return isOk(checkColor(value, scheme))
+	case COLOR_LIST:
+		return isOk(parseColorList(value, func(name string) Result[Color] {
+			return checkColor(name, scheme)
+		}))
+	case POINT:
+		return isOk(ParsePoint(value))
+	case POINT_LIST:
+		return isOk(ParsePointList(value))
+	case RECT:
+		return isOk(ParseRect(value))
+	case ARROW_TYPE:
+		return isOk(ParseArrowType(value))
+	case STYLE:
+		return isOk(ParseStyle(value))
+	case SPLINE_TYPE:
+		return isOk(ParseSplineType(value))
+	case ADD_DOUBLE:
+		return isOk(ParseDouble(strings.TrimPrefix(value, "+")))
+	case ADD_POINT:
+		return isOk(ParsePoint(strings.TrimPrefix(value, "+")))
+	case DOUBLE_LIST:
+		return isDoubleTuple(value, ":", 1, -1)
+	case VIEW_PORT:
+		return isViewPort(value)
+	case PACK_MODE:
+		return packModePattern.MatchString(value)
+	case START_TYPE:
+		return startTypePattern.MatchString(value)
+	}
+
+	if values, isEnumeration := enumerations[valueType]; isEnumeration {
+		for _, allowed := range values {
+			if value == allowed {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func parseDoubles(value string, separator string, minLen int, maxLen int) ([]float64, bool) {
+	fields := strings.Split(value, separator)
+	if len(fields) < minLen || (maxLen >= 0 && len(fields) > maxLen) {
+		return nil, false
+	}
+
+	numbers := make([]float64, len(fields))
+	for i, field := range fields {
+		number := ParseDouble(field)
+		if number.IsErr() {
+			return nil, false
+		}
+		numbers[i] = number.Unwrap()
+	}
+	return numbers, true
+}
+
+func isDoubleTuple(value string, separator string, minLen int, maxLen int) bool {
+	_, valid := parseDoubles(value, separator, minLen, maxLen)
+	return valid
+}
+
+// viewPort: "W,H,Z,x,y" or "W,H,Z,N" where the trailing fields are optional
+func isViewPort(value string) bool {
+	fields := strings.Split(value, ",")
+	if len(fields) == 4 {
+		return isDoubleTuple(strings.Join(fields[:3], ","), ",", 3, 3)
+	}
+	return isDoubleTuple(value, ",", 2, 5)
+}
@@ -0,0 +1,252 @@
+package attribute
+
+import (
+	"dot-parser/parser"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	colors := map[string]Color{
+		"#ff000080":     {255, 0, 0, 128},
+		"#00ff00":       {0, 255, 0, 255},
+		"red":           {255, 0, 0, 255},
+		"Dark Green":    {0, 100, 0, 255},
+		"/svg/green":    {0, 128, 0, 255},
+		"//green":       {0, 255, 0, 255},
+		"0.0 1.0 1.0":   {255, 0, 0, 255},
+		"0.333,1.0,1.0": {1, 255, 0, 255},
+		"transparent":   {255, 255, 254, 0},
+	}
+
+	for value, expected := range colors {
+		res := ParseColor(value)
+		if res.IsErr() {
+			t.Errorf("Expected color for '%s', failed with %s", value, res.UnwrapErr())
+		} else if color := res.Unwrap(); color != expected {
+			t.Errorf("Expected %v for '%s', got %v", expected, value, color)
+		}
+	}
+
+	for _, value := range []string{"", "#ff00", "notacolor", "/brewer/red", "1,2"} {
+		if res := ParseColor(value); res.IsOk() {
+			t.Errorf("Expected error for '%s', got %v", value, res.Unwrap())
+		}
+	}
+
+	if green := ParseColorInScheme("green", "svg"); green.IsErr() || green.Unwrap().G != 128 {
+		t.Errorf("Expected svg green, got %v", green)
+	}
+
+	if formatted := FormatColor(Color{255, 0, 0, 128}); formatted != "#ff000080" {
+		t.Errorf("Expected '#ff000080', got '%s'", formatted)
+	}
+}
+
+func TestParseColorList(t *testing.T) {
+	res := ParseColorList("red:blue;0.3")
+	if res.IsErr() {
+		t.Fatalf("Expected color list, failed with %s", res.UnwrapErr())
+	}
+
+	colors := res.Unwrap()
+	if len(colors) != 2 || colors[0].Fraction.IsSome() || colors[1].Fraction.OrElse(0) != 0.3 {
+		t.Fatalf("Expected 'red' and 'blue' with fraction 0.3, got %v", colors)
+	}
+
+	if formatted := FormatColorList(colors); formatted != "#ff0000:#0000ff;0.3" {
+		t.Fatalf("Expected '#ff0000:#0000ff;0.3', got '%s'", formatted)
+	}
+
+	if res := ParseColorList("red;0.8:blue;0.8"); res.IsOk() {
+		t.Fatalf("Expected error for fractions greater than 1, got %v", res.Unwrap())
+	}
+
+	if res := ParseColorList("red;nan:blue"); res.IsOk() {
+		t.Fatalf("Expected error for a fraction that is not a number, got %v", res.Unwrap())
+	}
+}
+
+func TestParseDouble(t *testing.T) {
+	for _, value := range []string{"1", " -2.5 ", ".5", "3.", "1e-3"} {
+		if res := ParseDouble(value); res.IsErr() {
+			t.Errorf("Expected '%s' to be a double, failed with %s", value, res.UnwrapErr())
+		}
+	}
+
+	for _, value := range []string{"nan", "inf", "+Inf", "1e400", "0x1p-2", "1_0", ""} {
+		if res := ParseDouble(value); res.IsOk() {
+			t.Errorf("Expected error for '%s', got %v", value, res.Unwrap())
+		}
+	}
+}
+
+func TestParsePointAndRect(t *testing.T) {
+	point := ParsePoint("1,2!")
+	if point.IsErr() {
+		t.Fatalf("Expected point, failed with %s", point.UnwrapErr())
+	}
+	if p := point.Unwrap(); p.X != 1 || p.Y != 2 || p.Z.IsSome() || !p.Fixed {
+		t.Fatalf("Expected fixed point (1,2), got %v", p)
+	}
+	if formatted := FormatPoint(point.Unwrap()); formatted != "1,2!" {
+		t.Fatalf("Expected '1,2!', got '%s'", formatted)
+	}
+
+	rect := ParseRect("0,0,100,200")
+	if rect.IsErr() || rect.Unwrap() != (Rect{0, 0, 100, 200}) {
+		t.Fatalf("Expected rect (0,0,100,200), got %v", rect)
+	}
+}
+
+func TestParseArrowType(t *testing.T) {
+	res := ParseArrowType("lteeoldiamond")
+	if res.IsErr() {
+		t.Fatalf("Expected arrowType, failed with %s", res.UnwrapErr())
+	}
+
+	arrows := res.Unwrap()
+	expected := []Arrow{{Shape: "tee", Side: LEFT_SIDE}, {Shape: "diamond", Open: true, Side: LEFT_SIDE}}
+	if len(arrows) != 2 || arrows[0] != expected[0] || arrows[1] != expected[1] {
+		t.Fatalf("Expected %v, got %v", expected, arrows)
+	}
+
+	if formatted := FormatArrowType(arrows); formatted != "lteeoldiamond" {
+		t.Fatalf("Expected 'lteeoldiamond', got '%s'", formatted)
+	}
+
+	if res := ParseArrowType("ediamond"); res.IsErr() || res.Unwrap()[0] != (Arrow{Shape: "diamond", Open: true}) {
+		t.Fatalf("Expected 'ediamond' to be read as 'odiamond', got %v", res)
+	}
+
+	for _, value := range []string{"", "arrow", "dotdotdotdotdot"} {
+		if res := ParseArrowType(value); res.IsOk() {
+			t.Errorf("Expected error for '%s', got %v", value, res.Unwrap())
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	res := ParseStyle("filled, setlinewidth(2), dashed")
+	if res.IsErr() {
+		t.Fatalf("Expected style, failed with %s", res.UnwrapErr())
+	}
+
+	items := res.Unwrap()
+	if len(items) != 3 || items[1].Name != "setlinewidth" || len(items[1].Args) != 1 || items[1].Args[0] != "2" {
+		t.Fatalf("Expected 3 style items with 'setlinewidth(2)', got %v", items)
+	}
+
+	if !HasStyle(items, "dashed") || HasStyle(items, "bold") {
+		t.Fatalf("Expected style 'dashed' but not 'bold', got %v", items)
+	}
+
+	if formatted := FormatStyle(items); formatted != "filled,setlinewidth(2),dashed" {
+		t.Fatalf("Expected 'filled,setlinewidth(2),dashed', got '%s'", formatted)
+	}
+}
+
+func TestParseSplineType(t *testing.T) {
+	res := ParseSplineType("e,10,20 1,2 3,4 5,6 7,8;0,0 1,1 2,2 3,3 4,4 5,5 6,6")
+	if res.IsErr() {
+		t.Fatalf("Expected splineType, failed with %s", res.UnwrapErr())
+	}
+
+	splines := res.Unwrap()
+	if len(splines) != 2 || splines[0].End.IsNone() || splines[0].Start.IsSome() || len(splines[1].Points) != 7 {
+		t.Fatalf("Expected 2 splines, the first with an end point, got %v", splines)
+	}
+
+	if res := ParseSplineType("1,2 3,4"); res.IsOk() {
+		t.Fatalf("Expected error for spline with 2 points, got %v", res.Unwrap())
+	}
+}
+
+func TestExpandEscString(t *testing.T) {
+	ctx := EscContext{Graph: "G", Tail: "a", Head: "b", IsDirect: true}
+	if expanded := ExpandEscString(`\E in \G\n`, ctx); expanded != `a->b in G\n` {
+		t.Fatalf("Expected 'a->b in G\\n', got '%s'", expanded)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	cases := map[string][]string{
+		`one`:              {"one"},
+		`one\ntwo\l`:       {"one", "two"},
+		`\"quoted\" \\ \r`: {`"quoted" \ `},
+		``:                 {""},
+	}
+
+	for value, expected := range cases {
+		if lines := SplitLines(value); !reflect.DeepEqual(lines, expected) {
+			t.Errorf("Expected %q for %q, got %q", expected, value, lines)
+		}
+	}
+}
+
+func TestInferType(t *testing.T) {
+	cases := []struct {
+		values   []string
+		expected ValueType
+	}{
+		{[]string{"1", "-2"}, INT},
+		{[]string{"1", "2.5", "1e3"}, DOUBLE},
+		{[]string{"1", "inf"}, STRING},
+		{[]string{"1", "red"}, STRING},
+		{nil, STRING},
+	}
+
+	for _, c := range cases {
+		if inferred := InferType(c.values); inferred != c.expected {
+			t.Errorf("Expected %s for %q, got %s", c.expected, c.values, inferred)
+		}
+	}
+}
+
+func TestInferColumns(t *testing.T) {
+	attributes := []parser.AttributeMap{
+		{"id": "1", "width": "2", "a-b": "x"},
+		{"dotid": "y", "width": "1.5", "a_b": "z"},
+	}
+	sanitize := func(key string) string {
+		return strings.ReplaceAll(key, "-", "_")
+	}
+	reserved := func(key string) bool {
+		return key == "id"
+	}
+
+	expected := []Column{
+		{"a-b", "dota_b", STRING},
+		{"a_b", "a_b", STRING},
+		{"dotid", "dotid", STRING},
+		{"id", "dotdotid", INT},
+		{"width", "width", DOUBLE},
+	}
+	if columns := InferColumns(attributes, sanitize, reserved, "dot"); !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected %v, got %v", expected, columns)
+	}
+}
+
+func TestFormatReal(t *testing.T) {
+	for value, expected := range map[float64]string{2: "2.0", 1.5: "1.5", -3: "-3.0"} {
+		if formatted := FormatReal(value); formatted != expected {
+			t.Errorf("Expected %s for %v, got %s", expected, value, formatted)
+		}
+	}
+}
+
+func TestParseAttribute(t *testing.T) {
+	attr := parser.SingleAttribute{Key: "penwidth", Value: "thick"}
+
+	res := ParseAttribute(attr, ParseDouble)
+	var valueErr *ValueError
+	if res.IsOk() || !errors.As(res.UnwrapErr(), &valueErr) || valueErr.Type != DOUBLE {
+		t.Fatalf("Expected double value error, got %v", res)
+	}
+
+	if formatted := FormatAttribute("color", Color{0, 0, 255, 255}, FormatColor); formatted.Value != "#0000ff" {
+		t.Fatalf("Expected '#0000ff', got %v", formatted)
+	}
+}
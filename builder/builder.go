@@ -0,0 +1,133 @@
+package builder
+
+import (
+	"dot-parser/option"
+	"dot-parser/parser"
+)
+
+// Builder constructs a parser.Graph statement by statement. The graph it
+// builds is the same AST ParseFile returns for the equivalent DOT source, so
+// edges are written with '->' when the graph is directed and '--' otherwise.
+type Builder struct {
+	isStrict bool
+	isDirect bool
+	name     option.Option[string]
+	stmts    []parser.Statement
+}
+
+func Graph() *Builder {
+	return &Builder{isDirect: false, name: option.None[string]()}
+}
+
+func Digraph() *Builder {
+	return &Builder{isDirect: true, name: option.None[string]()}
+}
+
+func (builder *Builder) Strict() *Builder {
+	builder.isStrict = true
+	return builder
+}
+
+func (builder *Builder) Name(name string) *Builder {
+	builder.name = option.Some(name)
+	return builder
+}
+
+func (builder *Builder) IsDirect() bool {
+	return builder.isDirect
+}
+
+// Attr adds a graph attribute statement "key = value"
+func (builder *Builder) Attr(key string, value string) *Builder {
+	return builder.add(&parser.SingleAttribute{Key: key, Value: value})
+}
+
+func (builder *Builder) GraphDefaults(attributes ...parser.AttributeMap) *Builder {
+	return builder.defaults(parser.GRAPH_LEVEL, attributes)
+}
+
+func (builder *Builder) NodeDefaults(attributes ...parser.AttributeMap) *Builder {
+	return builder.defaults(parser.NODE_LEVEL, attributes)
+}
+
+func (builder *Builder) EdgeDefaults(attributes ...parser.AttributeMap) *Builder {
+	return builder.defaults(parser.EDGE_LEVEL, attributes)
+}
+
+func (builder *Builder) Node(name string, attributes ...parser.AttributeMap) *Builder {
+	return builder.add(&parser.Node{ID: ID(name), Attributes: attributeList(attributes)})
+}
+
+func (builder *Builder) Edge(from string, to string, attributes ...parser.AttributeMap) *Builder {
+	return builder.EdgeID(ID(from), ID(to), attributes...)
+}
+
+// EdgeID adds an edge between node IDs, which can carry ports
+func (builder *Builder) EdgeID(from parser.NodeID, to parser.NodeID, attributes ...parser.AttributeMap) *Builder {
+	return builder.add(&parser.Edge{Lnode: from, Rnode: to, Attributes: attributeList(attributes)})
+}
+
+// Path adds the edges of the chain nodes[0] -> nodes[1] -> ... which, like an
+// edge statement with several arcs, share their attributes
+func (builder *Builder) Path(nodes []string, attributes ...parser.AttributeMap) *Builder {
+	shared := attributeList(attributes)
+	for i := 1; i < len(nodes); i++ {
+		builder.add(&parser.Edge{Lnode: ID(nodes[i-1]), Rnode: ID(nodes[i]), Attributes: shared})
+	}
+	return builder
+}
+
+// Subgraph adds a named subgraph whose statements are added by fill
+func (builder *Builder) Subgraph(name string, fill func(*Builder)) *Builder {
+	return builder.subgraph(option.Some(name), fill)
+}
+
+func (builder *Builder) AnonymousSubgraph(fill func(*Builder)) *Builder {
+	return builder.subgraph(option.None[string](), fill)
+}
+
+// Statement adds an already built statement
+func (builder *Builder) Statement(stmt parser.Statement) *Builder {
+	return builder.add(stmt)
+}
+
+func (builder *Builder) Build() parser.Graph {
+	return parser.Graph{
+		IsStrict:   builder.isStrict,
+		IsDirect:   builder.isDirect,
+		Name:       builder.name,
+		Statements: append([]parser.Statement(nil), builder.stmts...),
+	}
+}
+
+func ID(name string) parser.NodeID {
+	return parser.NodeID{Name: name, Port: option.None[string]()}
+}
+
+func Port(name string, port string) parser.NodeID {
+	return parser.NodeID{Name: name, Port: option.Some(port)}
+}
+
+func (builder *Builder) add(stmt parser.Statement) *Builder {
+	builder.stmts = append(builder.stmts, stmt)
+	return builder
+}
+
+func (builder *Builder) defaults(level parser.AttributeLevel, attributes []parser.AttributeMap) *Builder {
+	return builder.add(&parser.AttributeStmt{Level: level, Attributes: attributeList(attributes)})
+}
+
+func (builder *Builder) subgraph(name option.Option[string], fill func(*Builder)) *Builder {
+	sub := &Builder{isStrict: builder.isStrict, isDirect: builder.isDirect, name: name}
+	fill(sub)
+	return builder.add(&parser.Subgraph{Name: name, Statements: sub.stmts})
+}
+
+// attributeList mirrors the parser, which leaves the list nil when a
+// statement has no attribute list
+func attributeList(attributes []parser.AttributeMap) []parser.AttributeMap {
+	if len(attributes) == 0 {
+		return nil
+	}
+	return append([]parser.AttributeMap(nil), attributes...)
+}
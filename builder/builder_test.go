@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"dot-parser/internal/testutil"
+	"dot-parser/parser"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuilderMatchesParser(t *testing.T) {
+	res := parser.ParseFile(strings.NewReader(`strict digraph G {
+		rankdir = LR
+		node [ shape = box ]
+		a [ color = red ]
+		a -> b -> c [ weight = 2 ]
+		a:p -> c
+		subgraph cluster_0 { label = inner; d }
+		{ e }
+	}`))
+	if res.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", res.UnwrapErr())
+	}
+	parsed := res.Unwrap()
+	testutil.ClearPositions(parsed.Statements)
+
+	built := Digraph().Strict().Name("G").
+		Attr("rankdir", "LR").
+		NodeDefaults(parser.AttributeMap{"shape": "box"}).
+		Node("a", parser.AttributeMap{"color": "red"}).
+		Path([]string{"a", "b", "c"}, parser.AttributeMap{"weight": "2"}).
+		EdgeID(Port("a", "p"), ID("c")).
+		Subgraph("cluster_0", func(sub *Builder) {
+			sub.Attr("label", "inner").Node("d")
+		}).
+		AnonymousSubgraph(func(sub *Builder) {
+			sub.Node("e")
+		}).
+		Build()
+
+	if !reflect.DeepEqual(parsed, built) {
+		t.Fatalf("Expected built graph to match parsed graph\nparsed: %#v\nbuilt:  %#v", parsed, built)
+	}
+}
+
+func TestBuilderGraphKind(t *testing.T) {
+	graph := Graph().Edge("a", "b").Build()
+
+	if graph.IsDirect || graph.IsStrict || graph.Name.IsSome() {
+		t.Fatalf("Expected anonymous non strict undirected graph, got %#v", graph)
+	}
+
+	if len(graph.Statements) != 1 {
+		t.Fatalf("Expected 1 statement, got %#v", graph.Statements)
+	}
+}
@@ -0,0 +1,202 @@
+package canonical
+
+import (
+	"crypto/sha256"
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"dot-parser/printer"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// String writes the canonical DOT form of a graph, in which every graph that
+// resolves to the same model is written the same way:
+//   - the graph attributes come first, then every node with all of its
+//     resolved attributes, then every edge, then the subgraphs; no default
+//     attribute statements are written
+//   - nodes are sorted by name, edges by endpoints, ports and attributes,
+//     named subgraphs by name, followed by the anonymous ones sorted by
+//     their canonical form, and attributes by key
+//   - the endpoints of undirected edges are ordered by name
+//   - values of numeric attributes are written as the shortest equal numeral
+//   - IDs are quoted only when required
+func String(graph parser.Graph) string {
+	return Model(model.Resolve(graph))
+}
+
+// Model writes the canonical DOT form of a resolved graph
+func Model(graph *model.Graph) string {
+	var builder strings.Builder
+
+	if graph.IsStrict {
+		builder.WriteString("strict ")
+	}
+	arc := "--"
+	if graph.IsDirect {
+		builder.WriteString("digraph ")
+		arc = "->"
+	} else {
+		builder.WriteString("graph ")
+	}
+	if graph.Name.IsSome() {
+		builder.WriteString(printer.ID(graph.Name.Unwrap()) + " ")
+	}
+	builder.WriteString("{\n")
+
+	writeAttributes(&builder, graph.Attributes, "\t")
+
+	nodes := make([]string, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		nodes[i] = printer.ID(node.Name) + attributeList(node.Attributes)
+	}
+	sort.Strings(nodes)
+	writeLines(&builder, nodes, "\t")
+
+	edges := make([]edge, len(graph.Edges))
+	for i, e := range graph.Edges {
+		edges[i] = makeEdge(e, graph.IsDirect)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].less(edges[j])
+	})
+	for _, edge := range edges {
+		builder.WriteString("\t" + edge.tail + " " + arc + " " + edge.head + edge.attributes + "\n")
+	}
+
+	writeSubgraphs(&builder, graph.Subgraphs, "\t")
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// Hash returns the hex encoded SHA-256 of the canonical form of a graph, so
+// semantically equal graphs have the same hash
+func Hash(graph parser.Graph) string {
+	sum := sha256.Sum256([]byte(String(graph)))
+	return hex.EncodeToString(sum[:])
+}
+
+type edge struct {
+	tail       string
+	head       string
+	attributes string
+}
+
+func (e edge) less(other edge) bool {
+	if e.tail != other.tail {
+		return e.tail < other.tail
+	}
+	if e.head != other.head {
+		return e.head < other.head
+	}
+	return e.attributes < other.attributes
+}
+
+func makeEdge(e *model.Edge, isDirect bool) edge {
+	tail := nodeID(e.Tail.Name, e.TailPort.OrElse(""))
+	head := nodeID(e.Head.Name, e.HeadPort.OrElse(""))
+	if !isDirect && (e.Head.Name < e.Tail.Name || (e.Head.Name == e.Tail.Name && e.HeadPort.OrElse("") < e.TailPort.OrElse(""))) {
+		tail, head = head, tail
+	}
+	return edge{tail: tail, head: head, attributes: attributeList(e.Attributes)}
+}
+
+func nodeID(name string, port string) string {
+	if port != "" {
+		return printer.ID(name) + ":" + printer.ID(port)
+	}
+	return printer.ID(name)
+}
+
+func writeSubgraphs(builder *strings.Builder, subgraphs []*model.Subgraph, indent string) {
+	var named []*model.Subgraph
+	var anonymous []string
+	for _, subgraph := range subgraphs {
+		if subgraph.Name.IsSome() {
+			named = append(named, subgraph)
+		} else {
+			anonymous = append(anonymous, subgraphString(subgraph, indent))
+		}
+	}
+	sort.SliceStable(named, func(i, j int) bool {
+		return named[i].Name.Unwrap() < named[j].Name.Unwrap()
+	})
+	sort.Strings(anonymous)
+
+	for _, subgraph := range named {
+		builder.WriteString(subgraphString(subgraph, indent))
+	}
+	for _, subgraph := range anonymous {
+		builder.WriteString(subgraph)
+	}
+}
+
+func subgraphString(subgraph *model.Subgraph, indent string) string {
+	var builder strings.Builder
+	builder.WriteString(indent + "subgraph ")
+	if subgraph.Name.IsSome() {
+		builder.WriteString(printer.ID(subgraph.Name.Unwrap()) + " ")
+	}
+	builder.WriteString("{\n")
+
+	writeAttributes(&builder, subgraph.Attributes, indent+"\t")
+	nodes := make([]string, len(subgraph.Nodes))
+	for i, node := range subgraph.Nodes {
+		nodes[i] = printer.ID(node.Name)
+	}
+	sort.Strings(nodes)
+	writeLines(&builder, nodes, indent+"\t")
+	writeSubgraphs(&builder, subgraph.Subgraphs, indent+"\t")
+
+	builder.WriteString(indent + "}\n")
+	return builder.String()
+}
+
+func writeLines(builder *strings.Builder, lines []string, indent string) {
+	for _, line := range lines {
+		builder.WriteString(indent + line + "\n")
+	}
+}
+
+func writeAttributes(builder *strings.Builder, attributes parser.AttributeMap, indent string) {
+	for _, key := range attributes.Keys() {
+		builder.WriteString(indent + printer.ID(key) + "=" + printer.ID(Value(key, attributes[key])) + "\n")
+	}
+}
+
+func attributeList(attributes parser.AttributeMap) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	keys := attributes.Keys()
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = printer.ID(key) + "=" + printer.ID(Value(key, attributes[key]))
+	}
+	return " [" + strings.Join(items, ", ") + "]"
+}
+
+// Value normalises the value of an attribute whose types are all numeric,
+// e.g. "1.50" and "+1.5" are both written as "1.5". Other values are
+// returned as they are.
+func Value(key string, value string) string {
+	definition := attribute.Lookup(key)
+	if definition.IsNone() {
+		return value
+	}
+
+	for _, valueType := range definition.Unwrap().Types {
+		if valueType != attribute.INT && valueType != attribute.DOUBLE {
+			return value
+		}
+	}
+
+	number := attribute.ParseDouble(value)
+	if number.IsErr() {
+		return value
+	}
+	return attribute.FormatDouble(number.Unwrap())
+}
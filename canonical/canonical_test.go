@@ -0,0 +1,102 @@
+package canonical
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph "G" {
+		node [shape = "box"]
+		subgraph cluster_b { label = "B"; y }
+		subgraph cluster_a { x }
+		y -- x [ weight = 2.0 ; color = red ]
+		"x" [ width = "1.50" ]
+	}`)
+
+	expected := `graph G {
+	x [shape=box, width=1.5]
+	y [shape=box]
+	x -- y [color=red, weight=2]
+	subgraph cluster_a {
+		x
+	}
+	subgraph cluster_b {
+		label=B
+		y
+	}
+}
+`
+	if canonical := String(graph); canonical != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, canonical)
+	}
+
+	if again := String(testutil.ParseGraph(t, expected)); again != expected {
+		t.Fatalf("Expected canonical form to be stable, got:\n%s", again)
+	}
+}
+
+func TestHash(t *testing.T) {
+	same := []string{
+		`digraph { a -> b [label="x", penwidth=1]; b -> c; a [color=red] }`,
+		`digraph {
+			b -> c
+			a [color=blue]
+			a -> b [penwidth=1.0] [label=x]
+			a [color="red"]
+		}`,
+	}
+	different := []string{
+		`digraph { a -> b [label="x", penwidth=1]; b -> c }`,
+		`graph { a -- b [label="x", penwidth=1]; b -- c; a [color=red] }`,
+		`digraph { a -> b [label="x", penwidth=1]; c -> b; a [color=red] }`,
+	}
+
+	hash := Hash(testutil.ParseGraph(t, same[0]))
+	if len(hash) != 64 {
+		t.Fatalf("Expected a hex SHA-256, got %s", hash)
+	}
+	for _, input := range same[1:] {
+		if other := Hash(testutil.ParseGraph(t, input)); other != hash {
+			t.Errorf("Expected equal hashes for\n%s\nand\n%s\ngot:\n%s\n%s", same[0], input, String(testutil.ParseGraph(t, same[0])), String(testutil.ParseGraph(t, input)))
+		}
+	}
+	for _, input := range different {
+		if Hash(testutil.ParseGraph(t, input)) == hash {
+			t.Errorf("Expected different hashes for\n%s\nand\n%s", same[0], input)
+		}
+	}
+}
+
+func TestUndirectedEdges(t *testing.T) {
+	first := testutil.ParseGraph(t, `graph { b:s -- a:n }`)
+	second := testutil.ParseGraph(t, `graph { a:n -- b:s }`)
+	if String(first) != String(second) {
+		t.Fatalf("Expected equal canonical forms, got:\n%s\n%s", String(first), String(second))
+	}
+}
+
+func TestAnonymousSubgraphs(t *testing.T) {
+	first := testutil.ParseGraph(t, `digraph { subgraph { a } subgraph { b; subgraph { d } subgraph { c } } }`)
+	second := testutil.ParseGraph(t, `digraph { subgraph { b; subgraph { c } subgraph { d } } subgraph { a } }`)
+	if Hash(first) != Hash(second) {
+		t.Fatalf("Expected equal hashes, got:\n%s\n%s", String(first), String(second))
+	}
+}
+
+func TestValue(t *testing.T) {
+	values := [][3]string{
+		{"width", "1.50", "1.5"},
+		{"minlen", "02", "2"},
+		{"weight", "+3", "3"},
+		{"label", "1.50", "1.50"},
+		{"width", "wide", "wide"},
+		{"unknown", "1.0", "1.0"},
+	}
+
+	for _, value := range values {
+		if normalised := Value(value[0], value[1]); normalised != value[2] {
+			t.Errorf("Expected %s for %s=%s, got %s", value[2], value[0], value[1], normalised)
+		}
+	}
+}
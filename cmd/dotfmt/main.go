@@ -0,0 +1,109 @@
+// Command dotfmt formats DOT files. With no file arguments it formats the
+// standard input to the standard output.
+//
+//	dotfmt [-w] [-l] [-indent n] [-semicolons] [-quote needed|always|preserve] [-sort] [-pad] [-spaces] [file ...]
+package main
+
+import (
+	"bytes"
+	"dot-parser/format"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	write      = flag.Bool("w", false, "write the result to the source file instead of the standard output")
+	list       = flag.Bool("l", false, "list the files whose formatting differs")
+	indent     = flag.Int("indent", 0, "indent with this many spaces instead of a tab")
+	semicolons = flag.Bool("semicolons", false, "terminate statements with ';'")
+	quote      = flag.String("quote", "needed", "quote IDs when needed, always, or preserve the source quoting")
+	sortKeys   = flag.Bool("sort", false, "sort the attributes of each attribute list by key")
+	pad        = flag.Bool("pad", false, "pad attribute lists: [ key=value ]")
+	spaces     = flag.Bool("spaces", false, "write spaces around '=': key = value")
+)
+
+func options() (format.Options, error) {
+	options := format.DefaultOptions()
+	if *indent > 0 {
+		options.Indent = strings.Repeat(" ", *indent)
+	}
+	if *semicolons {
+		options.Semicolons = format.SEMICOLON_ALWAYS
+	}
+	switch *quote {
+	case "needed":
+		options.Quoting = format.QUOTE_AS_NEEDED
+	case "always":
+		options.Quoting = format.QUOTE_ALWAYS
+	case "preserve":
+		options.Quoting = format.QUOTE_PRESERVE
+	default:
+		return options, fmt.Errorf("invalid quoting policy %q", *quote)
+	}
+	options.SortAttributes = *sortKeys
+	options.PadAttributeLists = *pad
+	options.SpaceAroundEqual = *spaces
+	return options, nil
+}
+
+func formatFile(path string, options format.Options) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	formatted, err := format.Format(bytes.NewReader(source), options).Get()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if *list && formatted != string(source) {
+		fmt.Println(path)
+	}
+	if *write {
+		if formatted == string(source) {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(formatted), info.Mode().Perm())
+	}
+	if !*list {
+		_, err = io.WriteString(os.Stdout, formatted)
+	}
+	return err
+}
+
+func main() {
+	flag.Parse()
+
+	options, err := options()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if flag.NArg() == 0 {
+		formatted, err := format.Format(os.Stdin, options).Get()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Print(formatted)
+		return
+	}
+
+	status := 0
+	for _, path := range flag.Args() {
+		if err := formatFile(path, options); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
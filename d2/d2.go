@@ -0,0 +1,351 @@
+package d2
+
+import (
+	"dot-parser/alias"
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var keywords = []string{
+	"label", "shape", "style", "icon", "near", "width", "height", "direction",
+	"tooltip", "link", "constraint", "class", "classes", "vars", "layers",
+	"scenarios", "steps", "top", "left", "source-arrowhead",
+	"target-arrowhead", "grid-rows", "grid-columns", "grid-gap",
+	"vertical-gap", "horizontal-gap", "filled", "title", "null",
+}
+
+// shapes maps Graphviz node shapes to D2 shapes
+var shapes = map[string]string{
+	"box":           "rectangle",
+	"rect":          "rectangle",
+	"rectangle":     "rectangle",
+	"square":        "square",
+	"ellipse":       "oval",
+	"oval":          "oval",
+	"circle":        "circle",
+	"point":         "circle",
+	"doublecircle":  "circle",
+	"diamond":       "diamond",
+	"hexagon":       "hexagon",
+	"parallelogram": "parallelogram",
+	"cylinder":      "cylinder",
+	"note":          "page",
+	"folder":        "package",
+	"tab":           "package",
+	"plaintext":     "text",
+	"plain":         "text",
+	"none":          "text",
+}
+
+// DEFAULT_SHAPE is the shape of nodes without a shape, Graphviz draws them as
+// ellipses
+const DEFAULT_SHAPE = "oval"
+
+var directions = map[string]string{"TB": "down", "LR": "right", "BT": "up", "RL": "left"}
+
+type exporter struct {
+	graph   *model.Graph
+	aliases *alias.Aliases
+	// paths holds the key of each node prefixed by the keys of the clusters
+	// it is in, which is how D2 references it from the root
+	paths   map[*model.Node]string
+	builder strings.Builder
+}
+
+// Export writes a graph as a D2 diagram.
+//
+// The graph label becomes a title and rankdir the direction. Nodes are
+// written with their label, the D2 shape closest to their shape, an oval
+// when they have none and a rectangle when no shape is close, and their
+// fill, stroke and font colors, pen width and dashed, dotted, bold, rounded
+// and invisible styles. Clusters become containers holding their nodes and
+// clusters, other subgraphs are flattened. Edges become connections with
+// arrows for directed graphs, or according to dir, and with their label,
+// arrow shapes, color, width and style. IDs that are not identifiers, or are
+// D2 keywords, are replaced by aliases, compared ignoring case as D2 keys
+// are; the other attributes and ports are left out.
+func Export(graph parser.Graph) string {
+	return ExportModel(model.Resolve(graph))
+}
+
+func ExportModel(graph *model.Graph) string {
+	e := exporter{graph: graph, aliases: alias.NewIgnoringCase(keywords...), paths: make(map[*model.Node]string)}
+	for _, node := range graph.Nodes {
+		e.aliases.Get(node.Name)
+	}
+
+	e.header()
+	placement := graph.Placement((*model.Subgraph).IsCluster)
+	e.nodes(placement, nil, "", "")
+	e.clusters(graph.Subgraphs, placement, "", "")
+	for _, edge := range graph.Edges {
+		e.edge(edge)
+	}
+	return e.builder.String()
+}
+
+// text writes a label as a quoted D2 string, with a \n for each line break
+func text(label string) string {
+	lines := attribute.SplitLines(label)
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "\\", "\\\\")
+		lines[i] = strings.ReplaceAll(line, "\"", "\\\"")
+	}
+	return "\"" + strings.Join(lines, "\\n") + "\""
+}
+
+// color formats a Graphviz color as a quoted hexadecimal D2 color
+func color(value string) (string, bool) {
+	color := attribute.ParseColor(value)
+	if color.IsErr() {
+		return "", false
+	}
+	return "\"" + attribute.FormatColor(color.Unwrap()) + "\"", true
+}
+
+// width rounds a pen width to a D2 stroke width, between 1 and 15
+func width(value string) (string, bool) {
+	width := attribute.ParseDouble(value)
+	if width.IsErr() {
+		return "", false
+	}
+	return fmt.Sprint(int(math.Min(15, math.Max(1, math.Round(width.Unwrap()))))), true
+}
+
+func (e *exporter) header() {
+	if label, exists := e.graph.Attributes["label"]; exists {
+		label = attribute.ExpandEscString(label, attribute.EscContext{Graph: e.graph.Name.OrElse("")})
+		e.builder.WriteString("title: " + text(label) + " {\n  shape: text\n  near: top-center\n}\n")
+	}
+	if direction, exists := directions[e.graph.Attributes["rankdir"]]; exists {
+		e.builder.WriteString("direction: " + direction + "\n")
+	}
+}
+
+// block writes a key with its label and the fields in braces
+func (e *exporter) block(indent string, key string, label string, fields []string) {
+	line := indent + key
+	if label != key {
+		line += ": " + text(label)
+	}
+	if len(fields) == 0 {
+		e.builder.WriteString(line + "\n")
+		return
+	}
+	e.builder.WriteString(line + " {\n")
+	for _, field := range fields {
+		e.builder.WriteString(indent + "  " + field + "\n")
+	}
+	e.builder.WriteString(indent + "}\n")
+}
+
+// nodes writes the nodes placed in subgraph, or in no cluster when nil
+func (e *exporter) nodes(placement map[*model.Node]*model.Subgraph, subgraph *model.Subgraph, prefix string, indent string) {
+	for _, node := range e.graph.Nodes {
+		if placement[node] != subgraph {
+			continue
+		}
+
+		key := e.aliases.Get(node.Name)
+		e.paths[node] = prefix + key
+
+		label := node.Name
+		if value, exists := node.Attributes["label"]; exists {
+			label = attribute.ExpandEscString(value, attribute.EscContext{Graph: e.graph.Name.OrElse(""), Node: node.Name})
+		}
+		shape := DEFAULT_SHAPE
+		if mapped, exists := shapes[node.Attributes["shape"]]; exists {
+			shape = mapped
+		} else if _, exists := node.Attributes["shape"]; exists {
+			shape = "rectangle"
+		}
+
+		var fields []string
+		if shape != "rectangle" {
+			fields = append(fields, "shape: "+shape)
+		}
+		if node.Attributes["shape"] == "doublecircle" {
+			fields = append(fields, "style.double-border: true")
+		}
+		e.block(indent, key, label, append(fields, styleFields(node.Attributes, true)...))
+	}
+}
+
+func (e *exporter) clusters(subgraphs []*model.Subgraph, placement map[*model.Node]*model.Subgraph, prefix string, indent string) {
+	for _, subgraph := range subgraphs {
+		if !subgraph.IsCluster() {
+			e.clusters(subgraph.Subgraphs, placement, prefix, indent)
+			continue
+		}
+
+		name := subgraph.Name.Unwrap()
+		key := e.aliases.Unique(name)
+
+		// Graphviz shows no title for clusters without a label
+		label := ""
+		if value, exists := subgraph.Attributes["label"]; exists {
+			label = attribute.ExpandEscString(value, attribute.EscContext{Graph: name})
+		}
+		fields := styleFields(subgraph.Attributes, true)
+		if bgcolor, ok := color(subgraph.Attributes["bgcolor"]); ok {
+			fields = append(fields, "style.fill: "+bgcolor)
+		}
+		if pencolor, ok := color(subgraph.Attributes["pencolor"]); ok {
+			fields = append(fields, "style.stroke: "+pencolor)
+		}
+
+		e.builder.WriteString(indent + key + ": " + text(label) + " {\n")
+		for _, field := range fields {
+			e.builder.WriteString(indent + "  " + field + "\n")
+		}
+		e.nodes(placement, subgraph, prefix+key+".", indent+"  ")
+		e.clusters(subgraph.Subgraphs, placement, prefix+key+".", indent+"  ")
+		e.builder.WriteString(indent + "}\n")
+	}
+}
+
+func (e *exporter) edge(edge *model.Edge) {
+	tail, head := edge.Tail, edge.Head
+	dir := "none"
+	if e.graph.IsDirect {
+		dir = "forward"
+	}
+	if value := edge.Attributes["dir"]; value == "forward" || value == "back" || value == "both" || value == "none" {
+		dir = value
+	}
+
+	var fields []string
+	arrowhead, arrowtail := edge.Attributes["arrowhead"], edge.Attributes["arrowtail"]
+	if dir == "back" {
+		tail, head = head, tail
+		arrowhead, arrowtail = arrowtail, ""
+		dir = "forward"
+	}
+	hasTail, hasHead := dir == "both" && arrowtail != "none", dir != "none" && arrowhead != "none"
+	if hasTail {
+		fields = append(fields, arrowFields("source-arrowhead", arrowtail)...)
+	}
+	if hasHead {
+		fields = append(fields, arrowFields("target-arrowhead", arrowhead)...)
+	}
+
+	connection := "--"
+	switch {
+	case hasTail && hasHead:
+		connection = "<->"
+	case hasTail:
+		connection = "<-"
+	case hasHead:
+		connection = "->"
+	}
+
+	line := e.paths[tail] + " " + connection + " " + e.paths[head]
+	if value, exists := edge.Attributes["label"]; exists {
+		label := attribute.ExpandEscString(value, attribute.EscContext{
+			Graph:    e.graph.Name.OrElse(""),
+			Tail:     edge.Tail.Name,
+			Head:     edge.Head.Name,
+			IsDirect: e.graph.IsDirect,
+		})
+		line += ": " + text(label)
+	}
+
+	fields = append(fields, styleFields(edge.Attributes, false)...)
+	if len(fields) == 0 {
+		e.builder.WriteString(line + "\n")
+		return
+	}
+	e.builder.WriteString(line + " {\n")
+	for _, field := range fields {
+		e.builder.WriteString("  " + field + "\n")
+	}
+	e.builder.WriteString("}\n")
+}
+
+// arrowFields maps an arrow shape to the D2 arrowhead, leaving the default
+// triangle for the shapes that have no close one
+func arrowFields(arrowhead string, value string) []string {
+	shape, filled := "", ""
+	switch value {
+	case "dot", "odot":
+		shape = "circle"
+	case "diamond", "odiamond":
+		shape = "diamond"
+	case "box", "obox":
+		shape = "box"
+	case "vee":
+		shape = "arrow"
+	case "empty", "onormal":
+		shape = "triangle"
+	}
+	if strings.HasPrefix(value, "o") || value == "empty" {
+		filled = "false"
+	} else if shape != "" {
+		filled = "true"
+	}
+
+	var fields []string
+	if shape != "" {
+		fields = append(fields, arrowhead+".shape: "+shape)
+	}
+	if filled != "" {
+		fields = append(fields, arrowhead+".style.filled: "+filled)
+	}
+	return fields
+}
+
+// styleFields writes the style fields of a node, cluster or edge, only the
+// fillable ones have a fill
+func styleFields(attributes parser.AttributeMap, fillable bool) []string {
+	var filled, rounded bool
+	var fields []string
+	if items := attribute.ParseStyle(attributes["style"]); items.IsOk() {
+		for _, item := range items.Unwrap() {
+			switch item.Name {
+			case "filled":
+				filled = true
+			case "rounded":
+				rounded = true
+			case "dashed":
+				fields = append(fields, "style.stroke-dash: 5")
+			case "dotted":
+				fields = append(fields, "style.stroke-dash: 2")
+			case "bold":
+				if _, exists := attributes["penwidth"]; !exists {
+					fields = append(fields, "style.stroke-width: 2")
+				}
+			case "invis":
+				fields = append(fields, "style.opacity: 0")
+			}
+		}
+	}
+	if rounded {
+		fields = append(fields, "style.border-radius: 8")
+	}
+
+	stroke, hasStroke := color(attributes["color"])
+	if filled && fillable {
+		fill, ok := color(attributes["fillcolor"])
+		if !ok {
+			fill, ok = stroke, hasStroke
+		}
+		if !ok {
+			fill = "\"#d3d3d3\""
+		}
+		fields = append(fields, "style.fill: "+fill)
+	}
+	if hasStroke {
+		fields = append(fields, "style.stroke: "+stroke)
+	}
+	if width, ok := width(attributes["penwidth"]); ok {
+		fields = append(fields, "style.stroke-width: "+width)
+	}
+	if fontcolor, ok := color(attributes["fontcolor"]); ok {
+		fields = append(fields, "style.font-color: "+fontcolor)
+	}
+	return fields
+}
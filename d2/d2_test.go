@@ -0,0 +1,94 @@
+package d2
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		rankdir=LR
+		label="Flow"
+		node [shape=box]
+		start [shape=doublecircle, label="Start\nhere"]
+		label [shape=cylinder, style=filled, fillcolor="#ff0000", fontcolor=white]
+		subgraph cluster_0 {
+			label="Phase \"1\""
+			bgcolor=lightgrey
+			x -> y [style=dashed, label="go"]
+			subgraph cluster_inner { "x y" [style=rounded] }
+		}
+		{ X }
+		start -> x [color=blue, penwidth=2]
+		y -> label [dir=both, arrowtail=odot]
+		X -> "x y" [dir=back, arrowhead=none]
+	}`)
+
+	expected := `title: "Flow" {
+  shape: text
+  near: top-center
+}
+direction: right
+start: "Start\nhere" {
+  shape: circle
+  style.double-border: true
+}
+label_1: "label" {
+  shape: cylinder
+  style.fill: "#ff0000"
+  style.font-color: "#ffffff"
+}
+X_1: "X"
+cluster_0: "Phase \"1\"" {
+  style.fill: "#d3d3d3"
+  x
+  y
+  cluster_inner: "" {
+    x_y: "x y" {
+      style.border-radius: 8
+    }
+  }
+}
+cluster_0.x -> cluster_0.y: "go" {
+  style.stroke-dash: 5
+}
+start -> cluster_0.x {
+  style.stroke: "#0000ff"
+  style.stroke-width: 2
+}
+cluster_0.y <-> label_1 {
+  source-arrowhead.shape: circle
+  source-arrowhead.style.filled: false
+}
+cluster_0.cluster_inner.x_y -> X_1
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportUndirected(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { a -- b; b -- c [dir=forward, arrowhead=diamond]; c -- a [style=invis] }`)
+
+	expected := `a {
+  shape: oval
+}
+b {
+  shape: oval
+}
+c {
+  shape: oval
+}
+a -- b
+b -> c {
+  target-arrowhead.shape: diamond
+  target-arrowhead.style.filled: true
+}
+c -- a {
+  style.opacity: 0
+}
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
@@ -0,0 +1,338 @@
+package diff
+
+import (
+	"dot-parser/model"
+	"dot-parser/option"
+	"dot-parser/parser"
+	"fmt"
+	"strings"
+)
+
+type AttributeChange struct {
+	Key string
+	Old option.Option[string]
+	New option.Option[string]
+}
+
+type NodeChange struct {
+	Name       string
+	Attributes []AttributeChange
+}
+
+type EdgeChange struct {
+	Old        *model.Edge
+	New        *model.Edge
+	Attributes []AttributeChange
+}
+
+type SubgraphChange struct {
+	Name         string
+	Attributes   []AttributeChange
+	AddedNodes   []string
+	RemovedNodes []string
+}
+
+// Changes lists the differences between two resolved graphs. Nodes are
+// matched by name, subgraphs by name (anonymous subgraphs by their position
+// among the anonymous subgraphs of their parent) and edges by their
+// endpoints and ports, in declaration order when they are repeated.
+type Changes struct {
+	StrictChanged    bool
+	DirectChanged    bool
+	NameChanged      bool
+	Graph            []AttributeChange
+	AddedNodes       []*model.Node
+	RemovedNodes     []*model.Node
+	ChangedNodes     []NodeChange
+	AddedEdges       []*model.Edge
+	RemovedEdges     []*model.Edge
+	ChangedEdges     []EdgeChange
+	AddedSubgraphs   []string
+	RemovedSubgraphs []string
+	ChangedSubgraphs []SubgraphChange
+}
+
+// Equal reports whether two graphs are semantically equal, regardless of
+// source positions, attribute order and the order of statements
+func Equal(old parser.Graph, new parser.Graph) bool {
+	return Compare(old, new).IsEmpty()
+}
+
+func Compare(old parser.Graph, new parser.Graph) Changes {
+	return CompareModels(model.Resolve(old), model.Resolve(new))
+}
+
+func CompareModels(old *model.Graph, new *model.Graph) Changes {
+	changes := Changes{
+		StrictChanged: old.IsStrict != new.IsStrict,
+		DirectChanged: old.IsDirect != new.IsDirect,
+		NameChanged:   old.Name.OrElse("") != new.Name.OrElse("") || old.Name.IsSome() != new.Name.IsSome(),
+		Graph:         compareAttributes(old.Attributes, new.Attributes),
+	}
+	changes.compareNodes(old, new)
+	changes.compareEdges(old, new)
+	changes.compareSubgraphs(old, new)
+	return changes
+}
+
+func (changes Changes) IsEmpty() bool {
+	return !changes.StrictChanged && !changes.DirectChanged && !changes.NameChanged &&
+		len(changes.Graph) == 0 &&
+		len(changes.AddedNodes) == 0 && len(changes.RemovedNodes) == 0 && len(changes.ChangedNodes) == 0 &&
+		len(changes.AddedEdges) == 0 && len(changes.RemovedEdges) == 0 && len(changes.ChangedEdges) == 0 &&
+		len(changes.AddedSubgraphs) == 0 && len(changes.RemovedSubgraphs) == 0 && len(changes.ChangedSubgraphs) == 0
+}
+
+func (changes *Changes) compareNodes(old *model.Graph, new *model.Graph) {
+	for _, node := range old.Nodes {
+		other := new.Node(node.Name)
+		if other.IsNone() {
+			changes.RemovedNodes = append(changes.RemovedNodes, node)
+		} else if attributes := compareAttributes(node.Attributes, other.Unwrap().Attributes); len(attributes) > 0 {
+			changes.ChangedNodes = append(changes.ChangedNodes, NodeChange{Name: node.Name, Attributes: attributes})
+		}
+	}
+
+	for _, node := range new.Nodes {
+		if old.Node(node.Name).IsNone() {
+			changes.AddedNodes = append(changes.AddedNodes, node)
+		}
+	}
+}
+
+type edgeKey struct {
+	tail     string
+	tailPort string
+	head     string
+	headPort string
+}
+
+func keyOf(edge *model.Edge, isDirect bool) edgeKey {
+	key := edgeKey{
+		tail:     edge.Tail.Name,
+		tailPort: edge.TailPort.OrElse(""),
+		head:     edge.Head.Name,
+		headPort: edge.HeadPort.OrElse(""),
+	}
+	if !isDirect && (key.head < key.tail || (key.head == key.tail && key.headPort < key.tailPort)) {
+		key = edgeKey{tail: key.head, tailPort: key.headPort, head: key.tail, headPort: key.tailPort}
+	}
+	return key
+}
+
+func (changes *Changes) compareEdges(old *model.Graph, new *model.Graph) {
+	newEdges := make(map[edgeKey][]*model.Edge)
+	for _, edge := range new.Edges {
+		key := keyOf(edge, new.IsDirect)
+		newEdges[key] = append(newEdges[key], edge)
+	}
+
+	for _, edge := range old.Edges {
+		key := keyOf(edge, old.IsDirect)
+		candidates := newEdges[key]
+		if len(candidates) == 0 {
+			changes.RemovedEdges = append(changes.RemovedEdges, edge)
+			continue
+		}
+
+		other := candidates[0]
+		newEdges[key] = candidates[1:]
+		if attributes := compareAttributes(edge.Attributes, other.Attributes); len(attributes) > 0 {
+			changes.ChangedEdges = append(changes.ChangedEdges, EdgeChange{Old: edge, New: other, Attributes: attributes})
+		}
+	}
+
+	for _, edge := range new.Edges {
+		key := keyOf(edge, new.IsDirect)
+		for _, remaining := range newEdges[key] {
+			if remaining == edge {
+				changes.AddedEdges = append(changes.AddedEdges, edge)
+			}
+		}
+	}
+}
+
+// flattenSubgraphs returns every subgraph in pre-order together with the key
+// used to match it
+func flattenSubgraphs(subgraphs []*model.Subgraph, prefix string) ([]string, map[string]*model.Subgraph) {
+	var keys []string
+	byKey := make(map[string]*model.Subgraph)
+	anonymous := 0
+	for _, subgraph := range subgraphs {
+		var key string
+		if subgraph.Name.IsSome() {
+			key = subgraph.Name.Unwrap()
+		} else {
+			key = fmt.Sprintf("%s{#%d}", prefix, anonymous)
+			anonymous += 1
+		}
+		keys = append(keys, key)
+		byKey[key] = subgraph
+
+		childKeys, children := flattenSubgraphs(subgraph.Subgraphs, key+"/")
+		keys = append(keys, childKeys...)
+		for childKey, child := range children {
+			byKey[childKey] = child
+		}
+	}
+	return keys, byKey
+}
+
+func (changes *Changes) compareSubgraphs(old *model.Graph, new *model.Graph) {
+	oldKeys, oldSubgraphs := flattenSubgraphs(old.Subgraphs, "")
+	newKeys, newSubgraphs := flattenSubgraphs(new.Subgraphs, "")
+
+	for _, key := range oldKeys {
+		other, exists := newSubgraphs[key]
+		if !exists {
+			changes.RemovedSubgraphs = append(changes.RemovedSubgraphs, key)
+			continue
+		}
+
+		subgraph := oldSubgraphs[key]
+		change := SubgraphChange{
+			Name:         key,
+			Attributes:   compareAttributes(subgraph.Attributes, other.Attributes),
+			AddedNodes:   missingNodes(other.Nodes, subgraph.Nodes),
+			RemovedNodes: missingNodes(subgraph.Nodes, other.Nodes),
+		}
+		if len(change.Attributes) > 0 || len(change.AddedNodes) > 0 || len(change.RemovedNodes) > 0 {
+			changes.ChangedSubgraphs = append(changes.ChangedSubgraphs, change)
+		}
+	}
+
+	for _, key := range newKeys {
+		if _, exists := oldSubgraphs[key]; !exists {
+			changes.AddedSubgraphs = append(changes.AddedSubgraphs, key)
+		}
+	}
+}
+
+// missingNodes returns the names of the nodes that are in nodes but not in others
+func missingNodes(nodes []*model.Node, others []*model.Node) []string {
+	names := make(map[string]bool, len(others))
+	for _, other := range others {
+		names[other.Name] = true
+	}
+
+	var missing []string
+	for _, node := range nodes {
+		if !names[node.Name] {
+			missing = append(missing, node.Name)
+		}
+	}
+	return missing
+}
+
+func compareAttributes(old parser.AttributeMap, new parser.AttributeMap) []AttributeChange {
+	union := make(parser.AttributeMap, len(old)+len(new))
+	for key, value := range old {
+		union[key] = value
+	}
+	for key, value := range new {
+		union[key] = value
+	}
+
+	var changes []AttributeChange
+	for _, key := range union.Keys() {
+		oldValue, inOld := old[key]
+		newValue, inNew := new[key]
+		if inOld != inNew || oldValue != newValue {
+			change := AttributeChange{Key: key, Old: option.None[string](), New: option.None[string]()}
+			if inOld {
+				change.Old = option.Some(oldValue)
+			}
+			if inNew {
+				change.New = option.Some(newValue)
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func (change AttributeChange) String() string {
+	switch {
+	case change.Old.IsNone():
+		return fmt.Sprintf("+%s=%q", change.Key, change.New.Unwrap())
+	case change.New.IsNone():
+		return fmt.Sprintf("-%s=%q", change.Key, change.Old.Unwrap())
+	default:
+		return fmt.Sprintf("%s: %q -> %q", change.Key, change.Old.Unwrap(), change.New.Unwrap())
+	}
+}
+
+func formatEdge(edge *model.Edge) string {
+	tail, head := edge.Tail.Name, edge.Head.Name
+	if edge.TailPort.IsSome() {
+		tail += ":" + edge.TailPort.Unwrap()
+	}
+	if edge.HeadPort.IsSome() {
+		head += ":" + edge.HeadPort.Unwrap()
+	}
+	return tail + " " + head
+}
+
+func formatAttributeChanges(changes []AttributeChange) string {
+	formatted := make([]string, len(changes))
+	for i, change := range changes {
+		formatted[i] = change.String()
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// String lists the changes one per line, prefixed by '+' (added), '-'
+// (removed) or '~' (changed)
+func (changes Changes) String() string {
+	var lines []string
+	if changes.StrictChanged {
+		lines = append(lines, "~ strict")
+	}
+	if changes.DirectChanged {
+		lines = append(lines, "~ directed")
+	}
+	if changes.NameChanged {
+		lines = append(lines, "~ name")
+	}
+	if len(changes.Graph) > 0 {
+		lines = append(lines, "~ graph "+formatAttributeChanges(changes.Graph))
+	}
+	for _, node := range changes.RemovedNodes {
+		lines = append(lines, "- node "+node.Name)
+	}
+	for _, node := range changes.AddedNodes {
+		lines = append(lines, "+ node "+node.Name)
+	}
+	for _, node := range changes.ChangedNodes {
+		lines = append(lines, "~ node "+node.Name+" "+formatAttributeChanges(node.Attributes))
+	}
+	for _, edge := range changes.RemovedEdges {
+		lines = append(lines, "- edge "+formatEdge(edge))
+	}
+	for _, edge := range changes.AddedEdges {
+		lines = append(lines, "+ edge "+formatEdge(edge))
+	}
+	for _, edge := range changes.ChangedEdges {
+		lines = append(lines, "~ edge "+formatEdge(edge.Old)+" "+formatAttributeChanges(edge.Attributes))
+	}
+	for _, name := range changes.RemovedSubgraphs {
+		lines = append(lines, "- subgraph "+name)
+	}
+	for _, name := range changes.AddedSubgraphs {
+		lines = append(lines, "+ subgraph "+name)
+	}
+	for _, subgraph := range changes.ChangedSubgraphs {
+		line := "~ subgraph " + subgraph.Name
+		if len(subgraph.Attributes) > 0 {
+			line += " " + formatAttributeChanges(subgraph.Attributes)
+		}
+		for _, name := range subgraph.AddedNodes {
+			line += " +" + name
+		}
+		for _, name := range subgraph.RemovedNodes {
+			line += " -" + name
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestEqualIgnoresOrderAndPositions(t *testing.T) {
+	first := testutil.ParseGraph(t, "graph { a [ color = red, shape = box ]; b; a -- b; subgraph s { c } }")
+	second := testutil.ParseGraph(t, `graph {
+		b
+		a [ shape = box ][ color = red ]
+		b -- a
+		subgraph s { c }
+	}`)
+
+	if !Equal(first, second) {
+		t.Fatalf("Expected graphs to be equal, got changes:\n%s", Compare(first, second))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := testutil.ParseGraph(t, "digraph { a [ color = red ]; a -> b; b -> c [ weight = 1 ]; subgraph s { a } }")
+	new := testutil.ParseGraph(t, "digraph { a [ color = blue ]; a -> b; b -> c [ weight = 2 ]; c -> d; subgraph s { a b } }")
+
+	changes := Compare(old, new)
+	if changes.IsEmpty() || Equal(old, new) {
+		t.Fatalf("Expected graphs to differ")
+	}
+
+	if len(changes.ChangedNodes) != 1 || changes.ChangedNodes[0].Name != "a" {
+		t.Fatalf("Expected node 'a' to change, got %v", changes.ChangedNodes)
+	}
+
+	if change := changes.ChangedNodes[0].Attributes[0]; change.Key != "color" || change.Old.Unwrap() != "red" || change.New.Unwrap() != "blue" {
+		t.Fatalf("Expected 'color' to change from 'red' to 'blue', got %v", change)
+	}
+
+	if len(changes.AddedNodes) != 1 || changes.AddedNodes[0].Name != "d" || len(changes.RemovedNodes) != 0 {
+		t.Fatalf("Expected node 'd' to be added, got %v and %v", changes.AddedNodes, changes.RemovedNodes)
+	}
+
+	if len(changes.AddedEdges) != 1 || len(changes.ChangedEdges) != 1 || len(changes.RemovedEdges) != 0 {
+		t.Fatalf("Expected one added and one changed edge, got:\n%s", changes)
+	}
+
+	if len(changes.ChangedSubgraphs) != 1 || len(changes.ChangedSubgraphs[0].AddedNodes) != 1 {
+		t.Fatalf("Expected node 'b' to be added to subgraph 's', got %v", changes.ChangedSubgraphs)
+	}
+
+	expected := "+ node d\n~ node a color: \"red\" -> \"blue\"\n+ edge c d\n~ edge b c weight: \"1\" -> \"2\"\n~ subgraph s +b"
+	if formatted := changes.String(); formatted != expected {
+		t.Fatalf("Expected changes:\n%s\ngot:\n%s", expected, formatted)
+	}
+}
+
+func TestCompareDirection(t *testing.T) {
+	if Equal(testutil.ParseGraph(t, "digraph { a -> b }"), testutil.ParseGraph(t, "digraph { b -> a }")) {
+		t.Fatalf("Expected directed edges with swapped endpoints to differ")
+	}
+
+	if Equal(testutil.ParseGraph(t, "digraph { a -> b }"), testutil.ParseGraph(t, "graph { a -- b }")) {
+		t.Fatalf("Expected directed and undirected graphs to differ")
+	}
+}
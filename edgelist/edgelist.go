@@ -0,0 +1,200 @@
+package edgelist
+
+import (
+	"dot-parser/builder"
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type DecodeError struct {
+	Message string
+}
+
+func (err *DecodeError) Error() string {
+	return fmt.Sprintf("edge list error: %s", err.Message)
+}
+
+func makeDecodeError(format string, args ...interface{}) Result[parser.Graph] {
+	return Err[parser.Graph](&DecodeError{Message: fmt.Sprintf(format, args...)})
+}
+
+type Options struct {
+	// Separator separates the cells of a row, ',' for CSV and '\t' for TSV
+	Separator rune
+	// IsDirect tells whether the graphs read are digraphs; Matrix Market
+	// files tell it themselves
+	IsDirect bool
+	// Weight is the attribute holding the entries of adjacency matrices
+	Weight string
+}
+
+func DefaultOptions() Options {
+	return Options{Separator: ',', IsDirect: true, Weight: "weight"}
+}
+
+func (options Options) writer(out io.Writer) *csv.Writer {
+	writer := csv.NewWriter(out)
+	writer.Comma = options.Separator
+	return writer
+}
+
+// readRows reads a table with a header of at least minColumns cells
+func (options Options) readRows(reader io.Reader, table string, minColumns int) ([][]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = options.Separator
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, &DecodeError{Message: fmt.Sprintf("%s: %s", table, err)}
+	}
+	if len(rows) == 0 || len(rows[0]) < minColumns {
+		return nil, &DecodeError{Message: fmt.Sprintf("%s: the header must have at least %d columns", table, minColumns)}
+	}
+	return rows, nil
+}
+
+// EncodeEdges writes the edges of a graph as a table whose first columns are
+// the source and the target, followed by a column for each edge attribute,
+// in alphabetical order, with the ports as the tailport and headport
+// attributes. Cells of attributes an edge does not have are empty.
+func EncodeEdges(graph parser.Graph, options Options) string {
+	return EncodeModelEdges(model.Resolve(graph), options)
+}
+
+func EncodeModelEdges(graph *model.Graph, options Options) string {
+	rows := make([]parser.AttributeMap, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		rows[i] = edge.AttributesWithPorts()
+	}
+	columns := columns(rows)
+
+	var out strings.Builder
+	writer := options.writer(&out)
+	writer.Write(append([]string{"source", "target"}, columns...))
+	for i, edge := range graph.Edges {
+		writer.Write(append([]string{edge.Tail.Name, edge.Head.Name}, cells(rows[i], columns)...))
+	}
+	writer.Flush()
+	return out.String()
+}
+
+// EncodeNodes writes the nodes of a graph as a table whose first column is
+// the node ID, followed by a column for each node attribute, in
+// alphabetical order
+func EncodeNodes(graph parser.Graph, options Options) string {
+	return EncodeModelNodes(model.Resolve(graph), options)
+}
+
+func EncodeModelNodes(graph *model.Graph, options Options) string {
+	rows := make([]parser.AttributeMap, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		rows[i] = node.Attributes
+	}
+	columns := columns(rows)
+
+	var out strings.Builder
+	writer := options.writer(&out)
+	writer.Write(append([]string{"id"}, columns...))
+	for i, node := range graph.Nodes {
+		writer.Write(append([]string{node.Name}, cells(rows[i], columns)...))
+	}
+	writer.Flush()
+	return out.String()
+}
+
+func columns(rows []parser.AttributeMap) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, row := range rows {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func cells(row parser.AttributeMap, columns []string) []string {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = row[column]
+	}
+	return cells
+}
+
+// Decode reads a graph from a table of edges and, when nodes is not nil, a
+// table of nodes. The first two columns of the edges are the source and the
+// target, whatever their name, and the first column of the nodes is the node
+// ID; the other columns are attributes named by the header, empty cells are
+// left out and the tailport and headport columns are the ports of the
+// edges. Nodes are declared first, in the order of their table, so that
+// nodes without edges are kept.
+func Decode(edges io.Reader, nodes io.Reader, options Options) Result[parser.Graph] {
+	b := builder.Graph()
+	if options.IsDirect {
+		b = builder.Digraph()
+	}
+
+	if nodes != nil {
+		rows, err := options.readRows(nodes, "nodes", 1)
+		if err != nil {
+			return Err[parser.Graph](err)
+		}
+		for i, row := range rows[1:] {
+			if row[0] == "" {
+				return makeDecodeError("nodes: row %d: empty ID", i+2)
+			}
+			attributes := attributes(rows[0][1:], row[1:])
+			if len(attributes) > 0 {
+				b.Node(row[0], attributes)
+			} else {
+				b.Node(row[0])
+			}
+		}
+	}
+
+	rows, err := options.readRows(edges, "edges", 2)
+	if err != nil {
+		return Err[parser.Graph](err)
+	}
+	for i, row := range rows[1:] {
+		if row[0] == "" || row[1] == "" {
+			return makeDecodeError("edges: row %d: empty source or target", i+2)
+		}
+
+		attributes := attributes(rows[0][2:], row[2:])
+		tail, head := builder.ID(row[0]), builder.ID(row[1])
+		if port, exists := attributes["tailport"]; exists {
+			tail = builder.Port(row[0], port)
+			delete(attributes, "tailport")
+		}
+		if port, exists := attributes["headport"]; exists {
+			head = builder.Port(row[1], port)
+			delete(attributes, "headport")
+		}
+		if len(attributes) > 0 {
+			b.EdgeID(tail, head, attributes)
+		} else {
+			b.EdgeID(tail, head)
+		}
+	}
+	return Ok(b.Build())
+}
+
+func attributes(header []string, row []string) parser.AttributeMap {
+	attributes := parser.AttributeMap{}
+	for i, value := range row {
+		if value != "" {
+			attributes[header[i]] = value
+		}
+	}
+	return attributes
+}
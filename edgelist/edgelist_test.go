@@ -0,0 +1,117 @@
+package edgelist
+
+import (
+	"dot-parser/builder"
+	"dot-parser/diff"
+	"dot-parser/internal/testutil"
+	"dot-parser/parser"
+	"strings"
+	"testing"
+)
+
+func TestEncodeEdgesAndNodes(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		a [shape=box]
+		lonely [label="no, edges"]
+		a:out -> b [weight=3]
+		b -> a [color=red]
+	}`)
+
+	expectedEdges := "source,target,color,tailport,weight\na,b,,out,3\nb,a,red,,\n"
+	if edges := EncodeEdges(graph, DefaultOptions()); edges != expectedEdges {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expectedEdges, edges)
+	}
+	expectedNodes := "id,label,shape\na,,box\nlonely,\"no, edges\",\nb,,\n"
+	if nodes := EncodeNodes(graph, DefaultOptions()); nodes != expectedNodes {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expectedNodes, nodes)
+	}
+
+	decoded := Decode(strings.NewReader(expectedEdges), strings.NewReader(expectedNodes), DefaultOptions())
+	if decoded.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", decoded.UnwrapErr())
+	}
+	if !diff.Equal(graph, decoded.Unwrap()) {
+		t.Fatalf("Expected the decoded graph to equal the original, got %v", diff.Compare(graph, decoded.Unwrap()))
+	}
+}
+
+func TestDecodeTSV(t *testing.T) {
+	options := DefaultOptions()
+	options.Separator = '\t'
+	options.IsDirect = false
+
+	decoded := Decode(strings.NewReader("src\tdst\tweight\nx\ty\t2\ny\tz\t\n"), nil, options)
+	if decoded.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", decoded.UnwrapErr())
+	}
+
+	expected := builder.Graph().Edge("x", "y", parser.AttributeMap{"weight": "2"}).Edge("y", "z").Build()
+	if !diff.Equal(expected, decoded.Unwrap()) {
+		t.Fatalf("Unexpected graph: %v", diff.Compare(expected, decoded.Unwrap()))
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	inputs := []string{
+		"source\n",
+		"source,target\na,b\nc\n",
+		"source,target\na,\n",
+	}
+	for _, input := range inputs {
+		if decoded := Decode(strings.NewReader(input), nil, DefaultOptions()); decoded.IsOk() {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { a -- b [weight=2.5]; b -- c; c -- c; a -- b; d }`)
+
+	expected := ",a,b,c,d\na,0,3.5,0,0\nb,3.5,0,1,0\nc,0,1,1,0\nd,0,0,0,0\n"
+	options := DefaultOptions()
+	options.IsDirect = false
+	if matrix := EncodeMatrix(graph, options); matrix != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, matrix)
+	}
+
+	decoded := DecodeMatrix(strings.NewReader(expected), options)
+	if decoded.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", decoded.UnwrapErr())
+	}
+	merged := testutil.ParseGraph(t, `graph { a -- b [weight=3.5]; b -- c; c -- c; d }`)
+	if !diff.Equal(merged, decoded.Unwrap()) {
+		t.Fatalf("Unexpected graph: %v", diff.Compare(merged, decoded.Unwrap()))
+	}
+}
+
+func TestSparse(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph { a -> b [weight=2]; b -> a; "c d" }`)
+
+	expected := `%%MatrixMarket matrix coordinate integer general
+% node 1 a
+% node 2 b
+% node 3 c d
+3 3 2
+1 2 2
+2 1 1
+`
+	if sparse := EncodeSparse(graph, DefaultOptions()); sparse != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, sparse)
+	}
+
+	decoded := DecodeSparse(strings.NewReader(expected), DefaultOptions())
+	if decoded.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", decoded.UnwrapErr())
+	}
+	if !diff.Equal(graph, decoded.Unwrap()) {
+		t.Fatalf("Unexpected graph: %v", diff.Compare(graph, decoded.Unwrap()))
+	}
+
+	symmetric := DecodeSparse(strings.NewReader("%%MatrixMarket matrix coordinate pattern symmetric\n2 2 1\n2 1\n"), DefaultOptions())
+	if symmetric.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", symmetric.UnwrapErr())
+	}
+	if expected := testutil.ParseGraph(t, `graph { 2 -- 1 }`); !diff.Equal(expected, symmetric.Unwrap()) {
+		t.Fatalf("Unexpected graph: %v", diff.Compare(expected, symmetric.Unwrap()))
+	}
+}
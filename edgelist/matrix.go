@@ -0,0 +1,251 @@
+package edgelist
+
+import (
+	"bufio"
+	"dot-parser/attribute"
+	"dot-parser/builder"
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// weights sums the weights of the edges between each pair of nodes, an edge
+// without a numeric weight weighs 1. Undirected edges are counted in both
+// directions, except loops.
+func weights(graph *model.Graph, weight string) [][]float64 {
+	matrix := make([][]float64, len(graph.Nodes))
+	for i := range matrix {
+		matrix[i] = make([]float64, len(graph.Nodes))
+	}
+
+	for _, edge := range graph.Edges {
+		value := attribute.ParseDouble(edge.Attributes[weight]).OrElse(1)
+		tail, head := edge.Tail.Index, edge.Head.Index
+		matrix[tail][head] += value
+		if !graph.IsDirect && tail != head {
+			matrix[head][tail] += value
+		}
+	}
+	return matrix
+}
+
+// EncodeMatrix writes the dense adjacency matrix of a graph: the header row
+// and the first column hold the node IDs, in declaration order, and each
+// entry the sum of the weights of the edges from the node of its row to the
+// node of its column, 0 when there are none
+func EncodeMatrix(graph parser.Graph, options Options) string {
+	return EncodeModelMatrix(model.Resolve(graph), options)
+}
+
+func EncodeModelMatrix(graph *model.Graph, options Options) string {
+	matrix := weights(graph, options.Weight)
+
+	var out strings.Builder
+	writer := options.writer(&out)
+	header := []string{""}
+	for _, node := range graph.Nodes {
+		header = append(header, node.Name)
+	}
+	writer.Write(header)
+	for i, node := range graph.Nodes {
+		row := []string{node.Name}
+		for _, value := range matrix[i] {
+			row = append(row, attribute.FormatDouble(value))
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return out.String()
+}
+
+// DecodeMatrix reads a dense adjacency matrix as written by EncodeMatrix. A
+// non zero entry becomes an edge whose weight is the entry, unless it is 1;
+// undirected graphs only read the entries on and above the diagonal.
+func DecodeMatrix(reader io.Reader, options Options) Result[parser.Graph] {
+	rows, err := options.readRows(reader, "matrix", 1)
+	if err != nil {
+		return Err[parser.Graph](err)
+	}
+	names := rows[0][1:]
+	if len(rows)-1 != len(names) {
+		return makeDecodeError("matrix: %d columns but %d rows", len(names), len(rows)-1)
+	}
+
+	b := builder.Graph()
+	if options.IsDirect {
+		b = builder.Digraph()
+	}
+	for _, name := range names {
+		b.Node(name)
+	}
+	for i, row := range rows[1:] {
+		if row[0] != names[i] {
+			return makeDecodeError("matrix: row %d is %q, expected %q", i+2, row[0], names[i])
+		}
+		for j, cell := range row[1:] {
+			if !options.IsDirect && j < i {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+			if err != nil {
+				return makeDecodeError("matrix: row %d: invalid entry %q", i+2, cell)
+			}
+			addEdge(b, names[i], names[j], value, options.Weight)
+		}
+	}
+	return Ok(b.Build())
+}
+
+func addEdge(b *builder.Builder, tail string, head string, value float64, weight string) {
+	switch value {
+	case 0:
+	case 1:
+		b.Edge(tail, head)
+	default:
+		b.Edge(tail, head, parser.AttributeMap{weight: attribute.FormatDouble(value)})
+	}
+}
+
+// MATRIX_MARKET_HEADER starts the banner of Matrix Market files
+const MATRIX_MARKET_HEADER = "%%MatrixMarket matrix coordinate"
+
+// NODE_COMMENT starts the comments naming the rows of Matrix Market files
+const NODE_COMMENT = "% node "
+
+// EncodeSparse writes the adjacency matrix of a graph in the coordinate
+// Matrix Market format, with the same entries as EncodeMatrix. Undirected
+// graphs are written as symmetric matrices, keeping the entries on and
+// below the diagonal. Rows are numbered from 1 in declaration order and
+// named by a "% node <row> <ID>" comment.
+func EncodeSparse(graph parser.Graph, options Options) string {
+	return EncodeModelSparse(model.Resolve(graph), options)
+}
+
+func EncodeModelSparse(graph *model.Graph, options Options) string {
+	matrix := weights(graph, options.Weight)
+
+	field := "integer"
+	var entries []string
+	for i, row := range matrix {
+		for j, value := range row {
+			if value == 0 || (!graph.IsDirect && j > i) {
+				continue
+			}
+			if value != math.Trunc(value) {
+				field = "real"
+			}
+			entries = append(entries, fmt.Sprintf("%d %d %s", i+1, j+1, attribute.FormatDouble(value)))
+		}
+	}
+	symmetry := "general"
+	if !graph.IsDirect {
+		symmetry = "symmetric"
+	}
+
+	var out strings.Builder
+	out.WriteString(MATRIX_MARKET_HEADER + " " + field + " " + symmetry + "\n")
+	for i, node := range graph.Nodes {
+		out.WriteString(fmt.Sprintf("%s%d %s\n", NODE_COMMENT, i+1, node.Name))
+	}
+	out.WriteString(fmt.Sprintf("%d %d %d\n", len(graph.Nodes), len(graph.Nodes), len(entries)))
+	for _, entry := range entries {
+		out.WriteString(entry + "\n")
+	}
+	return out.String()
+}
+
+// DecodeSparse reads an adjacency matrix in the coordinate Matrix Market
+// format. Symmetric matrices are read as undirected graphs and general ones
+// as digraphs; pattern matrices have no weights. Rows are named by the
+// comments written by EncodeSparse, or by their number.
+func DecodeSparse(reader io.Reader, options Options) Result[parser.Graph] {
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	next := func() (string, bool) {
+		for scanner.Scan() {
+			lineNumber++
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" {
+				return line, true
+			}
+		}
+		return "", false
+	}
+
+	banner, _ := next()
+	fields := strings.Fields(strings.TrimPrefix(banner, MATRIX_MARKET_HEADER))
+	if !strings.HasPrefix(banner, MATRIX_MARKET_HEADER) || len(fields) != 2 {
+		return makeDecodeError("Matrix Market: line 1: expected %q followed by the field and the symmetry", MATRIX_MARKET_HEADER)
+	}
+	field, symmetry := fields[0], fields[1]
+	if field != "integer" && field != "real" && field != "pattern" {
+		return makeDecodeError("Matrix Market: unsupported field %q", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return makeDecodeError("Matrix Market: unsupported symmetry %q", symmetry)
+	}
+
+	names := make(map[int]string)
+	line, _ := next()
+	for strings.HasPrefix(line, "%") {
+		if strings.HasPrefix(line, NODE_COMMENT) {
+			fields := strings.SplitN(strings.TrimPrefix(line, NODE_COMMENT), " ", 2)
+			if row, err := strconv.Atoi(fields[0]); err == nil && len(fields) == 2 {
+				names[row] = fields[1]
+			}
+		}
+		line, _ = next()
+	}
+
+	var rows, columns, count int
+	if _, err := fmt.Sscanf(line, "%d %d %d", &rows, &columns, &count); err != nil || rows != columns || rows < 0 {
+		return makeDecodeError("Matrix Market: line %d: expected the size of a square matrix", lineNumber)
+	}
+
+	b := builder.Graph()
+	if symmetry == "general" {
+		b = builder.Digraph()
+	}
+	name := func(row int) string {
+		if name, exists := names[row]; exists {
+			return name
+		}
+		return strconv.Itoa(row)
+	}
+	for row := 1; row <= rows; row++ {
+		b.Node(name(row))
+	}
+
+	for i := 0; i < count; i++ {
+		line, ok := next()
+		if !ok {
+			return makeDecodeError("Matrix Market: expected %d entries, found %d", count, i)
+		}
+		entry := strings.Fields(line)
+		if (field == "pattern" && len(entry) != 2) || (field != "pattern" && len(entry) != 3) {
+			return makeDecodeError("Matrix Market: line %d: invalid entry", lineNumber)
+		}
+		row, rowErr := strconv.Atoi(entry[0])
+		column, columnErr := strconv.Atoi(entry[1])
+		if rowErr != nil || columnErr != nil || row < 1 || row > rows || column < 1 || column > rows {
+			return makeDecodeError("Matrix Market: line %d: invalid coordinates", lineNumber)
+		}
+		value := 1.0
+		if field != "pattern" {
+			var err error
+			if value, err = strconv.ParseFloat(entry[2], 64); err != nil {
+				return makeDecodeError("Matrix Market: line %d: invalid value %q", lineNumber, entry[2])
+			}
+		}
+		addEdge(b, name(row), name(column), value, options.Weight)
+	}
+	if err := scanner.Err(); err != nil {
+		return makeDecodeError("Matrix Market: %s", err)
+	}
+	return Ok(b.Build())
+}
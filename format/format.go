@@ -0,0 +1,377 @@
+package format
+
+import (
+	. "dot-parser/lexer"
+	"dot-parser/parser"
+	"dot-parser/printer"
+	. "dot-parser/result"
+	"io"
+	"sort"
+	"strings"
+)
+
+type SemicolonPolicy uint8
+
+const (
+	// Statements are not terminated
+	SEMICOLON_NEVER SemicolonPolicy = iota
+	// Every statement but subgraphs is terminated by ';'
+	SEMICOLON_ALWAYS
+)
+
+type QuotingPolicy uint8
+
+const (
+	// IDs are quoted only when they cannot be read back otherwise
+	QUOTE_AS_NEEDED QuotingPolicy = iota
+	// Every ID is quoted
+	QUOTE_ALWAYS
+	// IDs are quoted when they were quoted in the source, or when required
+	QUOTE_PRESERVE
+)
+
+type Options struct {
+	// Indent is written once per nesting level before each statement
+	Indent     string
+	Semicolons SemicolonPolicy
+	Quoting    QuotingPolicy
+	// SortAttributes orders the attributes of each attribute list by key
+	SortAttributes bool
+	// AttributeSeparator is written between the attributes of a list
+	AttributeSeparator string
+	// SpaceAroundEqual writes "key = value" instead of "key=value"
+	SpaceAroundEqual bool
+	// PadAttributeLists writes "[ key=value ]" instead of "[key=value]"
+	PadAttributeLists bool
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Indent:             "\t",
+		Semicolons:         SEMICOLON_NEVER,
+		Quoting:            QUOTE_AS_NEEDED,
+		SortAttributes:     false,
+		AttributeSeparator: ", ",
+		SpaceAroundEqual:   false,
+		PadAttributeLists:  false,
+	}
+}
+
+// Format lays out DOT source with one statement per line, indented by
+// nesting level. Comments are kept: the ones on their own lines stay before
+// the statement that follows them, the ones after a statement stay at the end
+// of its line. At most one blank line between statements is kept.
+//
+// The source must be a valid graph, otherwise the parsing error is returned.
+// Formatting the output again yields the same output.
+func Format(reader io.Reader, options Options) Result[string] {
+	source, err := io.ReadAll(reader)
+	if err != nil {
+		return Err[string](err)
+	}
+
+	if res := parser.ParseFile(strings.NewReader(string(source))); res.IsErr() {
+		return Err[string](res.UnwrapErr())
+	}
+
+	return Map(tokenize(strings.NewReader(string(source))), func(tokens []token) string {
+		f := formatter{options: options, tokens: tokens}
+		f.graph()
+		return f.builder.String()
+	})
+}
+
+type formatter struct {
+	options Options
+	tokens  []token
+	current int
+	builder strings.Builder
+}
+
+// line collects the words of an output line and the comments of the tokens
+// they were read from
+type line struct {
+	words       []string
+	leading     []comment
+	trailing    []string
+	blankBefore bool
+}
+
+func (f *formatter) peek() Token {
+	return f.tokens[f.current].Token()
+}
+
+// take consumes the next token, moving its comments to l
+func (f *formatter) take(l *line) token {
+	next := f.tokens[f.current]
+	f.current += 1
+
+	if len(l.words) == 0 && len(l.leading) == 0 {
+		l.blankBefore = next.blankBefore
+	}
+	for _, leading := range next.leading {
+		if len(l.words) > 0 {
+			leading.blankBefore = false
+		}
+		l.leading = append(l.leading, leading)
+	}
+	l.trailing = append(l.trailing, next.trailing...)
+	return next
+}
+
+func (f *formatter) word(l *line, word string) {
+	l.words = append(l.words, word)
+}
+
+func (f *formatter) id(t token) string {
+	lexeme := string(t.Lexeme())
+	switch {
+	case f.options.Quoting == QUOTE_ALWAYS:
+		return printer.Quote(lexeme)
+	case f.options.Quoting == QUOTE_PRESERVE && t.IsQuoted():
+		return printer.Quote(lexeme)
+	default:
+		return printer.ID(lexeme)
+	}
+}
+
+func (f *formatter) equal() string {
+	if f.options.SpaceAroundEqual {
+		return " = "
+	}
+	return "="
+}
+
+// write outputs the comments leading l and then l itself. Blank lines are
+// not written at the start of a block.
+func (f *formatter) write(l line, depth int, first bool) {
+	indent := strings.Repeat(f.options.Indent, depth)
+	for i, leading := range l.leading {
+		if leading.blankBefore && !(first && i == 0) {
+			f.builder.WriteString("\n")
+		}
+		if strings.HasPrefix(leading.text, "#") {
+			// '#' only starts a comment at the beginning of a line
+			f.builder.WriteString(leading.text + "\n")
+		} else {
+			f.builder.WriteString(indent + leading.text + "\n")
+		}
+	}
+
+	if l.blankBefore && !(first && len(l.leading) == 0) {
+		f.builder.WriteString("\n")
+	}
+	f.builder.WriteString(indent + strings.Join(l.words, " "))
+	f.builder.WriteString(trailingComments(l.trailing))
+	f.builder.WriteString("\n")
+}
+
+// trailingComments writes block comments before line comments, which have
+// to end the line
+func trailingComments(comments []string) string {
+	var block []string
+	var rest []string
+	for _, comment := range comments {
+		if strings.HasPrefix(comment, "/*") {
+			block = append(block, comment)
+		} else {
+			rest = append(rest, comment)
+		}
+	}
+
+	var out_string string
+	for _, comment := range append(block, rest...) {
+		out_string += " " + comment
+	}
+	return out_string
+}
+
+// Graph: STRICT? (GRAPH | DIGRAPH) ID? '{' Statement* '}' EOF
+func (f *formatter) graph() {
+	var header line
+	for f.peek() != OPEN_BRACE {
+		next := f.take(&header)
+		switch next.Token() {
+		case STRICT:
+			f.word(&header, "strict")
+		case GRAPH:
+			f.word(&header, "graph")
+		case DIGRAPH:
+			f.word(&header, "digraph")
+		default:
+			f.word(&header, f.id(next))
+		}
+	}
+	f.take(&header)
+	f.word(&header, "{")
+	f.write(header, 0, true)
+
+	f.block(1)
+
+	var closing line
+	f.take(&closing)
+	f.word(&closing, "}")
+	f.write(closing, 0, false)
+
+	eof := f.tokens[f.current]
+	for _, leading := range eof.leading {
+		if leading.blankBefore {
+			f.builder.WriteString("\n")
+		}
+		f.builder.WriteString(leading.text + "\n")
+	}
+}
+
+// block writes the statements up to the closing brace, which is left to the
+// caller, moving the comments leading the brace into the block
+func (f *formatter) block(depth int) {
+	first := true
+	for f.peek() != CLOSE_BRACE {
+		f.statement(depth, first)
+		first = false
+	}
+
+	closing := &f.tokens[f.current]
+	for i, leading := range closing.leading {
+		if leading.blankBefore && !(first && i == 0) {
+			f.builder.WriteString("\n")
+		}
+		f.builder.WriteString(strings.Repeat(f.options.Indent, depth) + leading.text + "\n")
+	}
+	closing.leading = nil
+	closing.blankBefore = false
+}
+
+func (f *formatter) statement(depth int, first bool) {
+	var l line
+	switch f.peek() {
+	case SUBGRAPH, OPEN_BRACE:
+		f.subgraph(l, depth, first)
+		return
+	case GRAPH, NODE, EDGE:
+		f.word(&l, levelKeywords[f.take(&l).Token()])
+		f.attributeLists(&l)
+	default:
+		f.nodeID(&l)
+		if f.peek() == EQUAL {
+			f.take(&l)
+			value := f.take(&l)
+			l.words[len(l.words)-1] += f.equal() + f.id(value)
+		} else {
+			for f.peek() == ARC || f.peek() == DIRECTED_ARC {
+				if f.take(&l).Token() == ARC {
+					f.word(&l, "--")
+				} else {
+					f.word(&l, "->")
+				}
+				f.nodeID(&l)
+			}
+			f.attributeLists(&l)
+		}
+	}
+
+	if f.peek() == SEMICOLON {
+		f.take(&l)
+	}
+	if f.options.Semicolons == SEMICOLON_ALWAYS {
+		l.words[len(l.words)-1] += ";"
+	}
+	f.write(l, depth, first)
+}
+
+// Subgraph: (SUBGRAPH ID?)? '{' Statement* '}' ';'?
+func (f *formatter) subgraph(header line, depth int, first bool) {
+	if f.peek() == SUBGRAPH {
+		f.take(&header)
+		f.word(&header, "subgraph")
+		if f.peek() == ID {
+			f.word(&header, f.id(f.take(&header)))
+		}
+	}
+	f.take(&header)
+
+	closing := f.tokens[f.current]
+	if closing.Token() == CLOSE_BRACE && len(closing.leading) == 0 {
+		f.take(&header)
+		f.word(&header, "{}")
+		if f.peek() == SEMICOLON {
+			f.take(&header)
+		}
+		f.write(header, depth, first)
+		return
+	}
+
+	f.word(&header, "{")
+	f.write(header, depth, first)
+	f.block(depth + 1)
+
+	var footer line
+	f.take(&footer)
+	f.word(&footer, "}")
+	if f.peek() == SEMICOLON {
+		f.take(&footer)
+	}
+	f.write(footer, depth, false)
+}
+
+// NodeId: ID (':' ID)?
+func (f *formatter) nodeID(l *line) {
+	name := f.id(f.take(l))
+	if f.peek() == COLON {
+		f.take(l)
+		name += ":" + f.id(f.take(l))
+	}
+	f.word(l, name)
+}
+
+var levelKeywords = map[Token]string{
+	GRAPH: "graph",
+	NODE:  "node",
+	EDGE:  "edge",
+}
+
+type attribute struct {
+	key   token
+	value token
+}
+
+// AttributeList: '[' (ID '=' ID (';' | ',')?)* ']'
+func (f *formatter) attributeLists(l *line) {
+	for f.peek() == OPEN_SQUARE_BRACKET {
+		f.take(l)
+
+		var attributes []attribute
+		for f.peek() != CLOSE_SQUARE_BRACKET {
+			key := f.take(l)
+			f.take(l)
+			value := f.take(l)
+			attributes = append(attributes, attribute{key: key, value: value})
+			if f.peek() == SEMICOLON || f.peek() == COMMA {
+				f.take(l)
+			}
+		}
+		f.take(l)
+
+		if f.options.SortAttributes {
+			sort.SliceStable(attributes, func(i, j int) bool {
+				return attributes[i].key.Lexeme() < attributes[j].key.Lexeme()
+			})
+		}
+		f.word(l, f.attributeList(attributes))
+	}
+}
+
+func (f *formatter) attributeList(attributes []attribute) string {
+	items := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		items[i] = f.id(attribute.key) + f.equal() + f.id(attribute.value)
+	}
+
+	if len(items) == 0 {
+		return "[]"
+	}
+	if f.options.PadAttributeLists {
+		return "[ " + strings.Join(items, f.options.AttributeSeparator) + " ]"
+	}
+	return "[" + strings.Join(items, f.options.AttributeSeparator) + "]"
+}
@@ -0,0 +1,133 @@
+package format
+
+import (
+	"dot-parser/diff"
+	"dot-parser/parser"
+	"strings"
+	"testing"
+)
+
+const source = `# preprocessor line
+/* header
+   comment */
+strict   digraph "G" { // graph comment
+  rankdir = LR ;  node [ shape = box , color=red ]
+  a:p -> "b" -> c [ label = "x \"y\"" ] ; // trailing
+
+
+  // leading
+  subgraph cluster_0 { d ; e -> f /* inner */ }
+  {}
+  edge [
+    color=blue // why blue
+    weight=2
+  ]
+  // end of block
+}
+// eof
+`
+
+func format(t *testing.T, input string, options Options) string {
+	res := Format(strings.NewReader(input), options)
+	if res.IsErr() {
+		t.Fatalf("Expected formatted source, failed with %s", res.UnwrapErr())
+	}
+	return res.Unwrap()
+}
+
+func TestFormat(t *testing.T) {
+	expected := `# preprocessor line
+/* header
+   comment */
+strict digraph G { // graph comment
+	rankdir=LR
+	node [shape=box, color=red]
+	a:p -> b -> c [label="x \"y\""] // trailing
+
+	// leading
+	subgraph cluster_0 {
+		d
+		e -> f /* inner */
+	}
+	{}
+	edge [color=blue, weight=2] // why blue
+	// end of block
+}
+// eof
+`
+	if formatted := format(t, source, DefaultOptions()); formatted != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+}
+
+func TestFormatOptions(t *testing.T) {
+	input := `graph { "a" -- b [z=1; "y"=2] subgraph { c } }`
+
+	options := Options{
+		Indent:             "  ",
+		Semicolons:         SEMICOLON_ALWAYS,
+		Quoting:            QUOTE_PRESERVE,
+		SortAttributes:     true,
+		AttributeSeparator: " ",
+		SpaceAroundEqual:   true,
+		PadAttributeLists:  true,
+	}
+	expected := `graph {
+  "a" -- b [ "y" = 2 z = 1 ];
+  subgraph {
+    c;
+  }
+}
+`
+	if formatted := format(t, input, options); formatted != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+
+	options = DefaultOptions()
+	options.Quoting = QUOTE_ALWAYS
+	expected = `graph {
+	"a" -- "b" ["z"="1", "y"="2"]
+	subgraph {
+		"c"
+	}
+}
+`
+	if formatted := format(t, input, options); formatted != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+}
+
+func TestFormatIdempotent(t *testing.T) {
+	sources := []string{
+		source,
+		`digraph{a->b;b->c}`,
+		"graph {\n\n\n/* a */ /* b */ x // c\n\n\n}\n\n// d",
+		"digraph {\n\ta /* first\n\tsecond */ -> b\n\tc\n}",
+		"graph { subgraph s {\n// only a comment\n} }",
+	}
+	optionSets := []Options{DefaultOptions(), {Indent: "    ", Semicolons: SEMICOLON_ALWAYS, Quoting: QUOTE_ALWAYS, AttributeSeparator: "; "}}
+
+	for _, input := range sources {
+		for _, options := range optionSets {
+			formatted := format(t, input, options)
+			if again := format(t, formatted, options); again != formatted {
+				t.Errorf("Expected formatting to be idempotent\nfirst:\n%s\nsecond:\n%s", formatted, again)
+			}
+		}
+	}
+}
+
+func TestFormatKeepsGraph(t *testing.T) {
+	original := parser.ParseFile(strings.NewReader(source)).Unwrap()
+	formatted := parser.ParseFile(strings.NewReader(format(t, source, DefaultOptions()))).Unwrap()
+
+	if !diff.Equal(original, formatted) {
+		t.Fatalf("Expected formatted graph to be equal, got changes:\n%s", diff.Compare(original, formatted))
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	if res := Format(strings.NewReader("digraph { a -> }"), DefaultOptions()); res.IsOk() {
+		t.Fatalf("Expected error, got:\n%s", res.Unwrap())
+	}
+}
@@ -0,0 +1,66 @@
+package format
+
+import (
+	. "dot-parser/lexer"
+	. "dot-parser/result"
+	"io"
+	"strings"
+)
+
+type comment struct {
+	text        string
+	blankBefore bool
+}
+
+// token is a non-comment token together with the comments around it: the
+// ones on the lines before it and the ones on the same line after it
+type token struct {
+	TokenData
+	leading     []comment
+	trailing    []string
+	blankBefore bool
+}
+
+func endLine(data TokenData) int {
+	return data.Position().Line() + strings.Count(string(data.Lexeme()), "\n")
+}
+
+// tokenize reads every token up to EOF, attaching each comment to the token
+// it belongs to. A comment starting on the line where the previous token ends
+// trails that token, any other comment leads the next token.
+func tokenize(reader io.Reader) Result[[]token] {
+	lex := MakeLexerWithComments(reader)
+
+	var tokens []token
+	var pending []comment
+	previousEnd := 0
+	trailingAllowed := false
+	for {
+		res := lex.Next().Unwrap()
+		if res.IsErr() {
+			return Err[[]token](res.UnwrapErr())
+		}
+
+		data := res.Unwrap()
+		blankBefore := previousEnd > 0 && data.Position().Line() > previousEnd+1
+		if data.Token() == COMMENT {
+			if trailingAllowed && data.Position().Line() == previousEnd {
+				last := &tokens[len(tokens)-1]
+				last.trailing = append(last.trailing, string(data.Lexeme()))
+			} else {
+				pending = append(pending, comment{text: string(data.Lexeme()), blankBefore: blankBefore})
+				trailingAllowed = false
+			}
+			previousEnd = endLine(data)
+			continue
+		}
+
+		tokens = append(tokens, token{TokenData: data, leading: pending, blankBefore: blankBefore})
+		if data.Token() == EOF {
+			return Ok(tokens)
+		}
+		pending = nil
+		previousEnd = endLine(data)
+		trailingAllowed = true
+	}
+}
@@ -0,0 +1,226 @@
+package gexf
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/option"
+	"dot-parser/parser"
+	"encoding/xml"
+	"fmt"
+)
+
+const namespace = "http://gexf.net/1.3"
+
+// Attribute classes
+const (
+	GRAPH_CLASS = "graph"
+	NODE_CLASS  = "node"
+	EDGE_CLASS  = "edge"
+)
+
+// reserved holds the titles Gephi gives a meaning to, by class
+var reserved = map[string]map[string]bool{
+	NODE_CLASS: {"id": true, "label": true},
+	EDGE_CLASS: {"id": true, "label": true, "source": true, "target": true, "type": true, "weight": true},
+}
+
+// RESERVED_PREFIX is prepended to the attributes named like a reserved title
+const RESERVED_PREFIX = "dot"
+
+type document struct {
+	XMLName xml.Name `xml:"gexf"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Meta    *meta    `xml:"meta"`
+	Graph   graph    `xml:"graph"`
+}
+
+type meta struct {
+	Description string `xml:"description"`
+}
+
+type graph struct {
+	DefaultEdgeType string       `xml:"defaultedgetype,attr"`
+	Mode            string       `xml:"mode,attr"`
+	Attributes      []attributes `xml:"attributes"`
+	Nodes           []node       `xml:"nodes>node"`
+	Edges           []edge       `xml:"edges>edge"`
+}
+
+type attributes struct {
+	Class      string          `xml:"class,attr"`
+	Mode       string          `xml:"mode,attr"`
+	Attributes []attributeType `xml:"attribute"`
+}
+
+type attributeType struct {
+	ID      string `xml:"id,attr"`
+	Title   string `xml:"title,attr"`
+	Type    string `xml:"type,attr"`
+	Default string `xml:"default,omitempty"`
+}
+
+type node struct {
+	ID        string     `xml:"id,attr"`
+	Label     string     `xml:"label,attr"`
+	AttValues *attValues `xml:"attvalues"`
+}
+
+type edge struct {
+	ID        string     `xml:"id,attr"`
+	Source    string     `xml:"source,attr"`
+	Target    string     `xml:"target,attr"`
+	Weight    string     `xml:"weight,attr,omitempty"`
+	AttValues *attValues `xml:"attvalues"`
+}
+
+type attValues struct {
+	Values []attValue `xml:"attvalue"`
+}
+
+type attValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// class holds the ids and the value types of the attributes of the graph,
+// of the nodes or of the edges
+type class struct {
+	columns []attribute.Column
+	ids     map[string]string
+	// weight is the numeric weight of the edges, written as the weight of the
+	// edge elements rather than declared
+	weight option.Option[attribute.Column]
+}
+
+func makeClass(name string, attributeMaps []parser.AttributeMap) (class, attributes) {
+	columns := attribute.InferColumns(attributeMaps, nil, func(key string) bool {
+		return reserved[name][key]
+	}, RESERVED_PREFIX)
+
+	c := class{ids: make(map[string]string)}
+	declarations := attributes{Class: name, Mode: "static"}
+	for _, column := range columns {
+		if name == EDGE_CLASS && column.Key == "weight" && column.Type != attribute.STRING {
+			c.weight = option.Some(column)
+			continue
+		}
+
+		c.columns = append(c.columns, column)
+		c.ids[column.Key] = fmt.Sprintf("%d", len(declarations.Attributes))
+		declarations.Attributes = append(declarations.Attributes, attributeType{ID: c.ids[column.Key], Title: column.Name, Type: typeName(column.Type)})
+	}
+	return c, declarations
+}
+
+func typeName(valueType attribute.ValueType) string {
+	switch valueType {
+	case attribute.INT:
+		return "integer"
+	case attribute.DOUBLE:
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+// attValues returns the values of the attributes declared in the class, nil
+// when there are none
+func (c class) attValues(attributes parser.AttributeMap) *attValues {
+	var values []attValue
+	for _, column := range c.columns {
+		if value, exists := attributes[column.Key]; exists {
+			values = append(values, attValue{For: c.ids[column.Key], Value: convert(value, column.Type)})
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return &attValues{Values: values}
+}
+
+func convert(value string, valueType attribute.ValueType) string {
+	switch valueType {
+	case attribute.INT:
+		return attribute.FormatInt(attribute.ParseInt(value).Unwrap())
+	case attribute.DOUBLE:
+		return attribute.FormatDouble(attribute.ParseDouble(value).Unwrap())
+	default:
+		return value
+	}
+}
+
+// Export writes a graph as a GEXF 1.3 document, the format of Gephi.
+//
+// Nodes are identified and labelled by their name, edges by their index.
+// The attributes of the nodes and of the edges, as well as the ports of the
+// edges as tailport and headport, are declared with the type inferred from
+// all the values of the attribute: integer when they all are integers,
+// double when they all are numbers and string otherwise. A numeric weight
+// becomes the weight of the edges; other attributes named like a GEXF
+// property, such as label, are prefixed with "dot". The graph label becomes
+// the description; the other graph attributes are declared in the graph
+// class, with their value as default. Subgraphs are left out.
+func Export(graph parser.Graph) string {
+	return ExportModel(model.Resolve(graph))
+}
+
+func ExportModel(graph *model.Graph) string {
+	nodeAttributes := make([]parser.AttributeMap, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		nodeAttributes[i] = node.Attributes
+	}
+	edgeAttributes := make([]parser.AttributeMap, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		edgeAttributes[i] = edge.AttributesWithPorts()
+	}
+	graphAttributes := make(parser.AttributeMap, len(graph.Attributes))
+	for key, value := range graph.Attributes {
+		if key != "label" {
+			graphAttributes[key] = value
+		}
+	}
+	graphClass, graphDeclarations := makeClass(GRAPH_CLASS, []parser.AttributeMap{graphAttributes})
+	for i, column := range graphClass.columns {
+		graphDeclarations.Attributes[i].Default = convert(graphAttributes[column.Key], column.Type)
+	}
+	nodeClass, nodeDeclarations := makeClass(NODE_CLASS, nodeAttributes)
+	edgeClass, edgeDeclarations := makeClass(EDGE_CLASS, edgeAttributes)
+
+	doc := document{Xmlns: namespace, Version: "1.3"}
+	if label, exists := graph.Attributes["label"]; exists {
+		doc.Meta = &meta{Description: label}
+	}
+
+	doc.Graph.DefaultEdgeType = "undirected"
+	if graph.IsDirect {
+		doc.Graph.DefaultEdgeType = "directed"
+	}
+	doc.Graph.Mode = "static"
+	for _, declarations := range []attributes{graphDeclarations, nodeDeclarations, edgeDeclarations} {
+		if len(declarations.Attributes) > 0 {
+			doc.Graph.Attributes = append(doc.Graph.Attributes, declarations)
+		}
+	}
+
+	for _, n := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node{ID: n.Name, Label: n.Name, AttValues: nodeClass.attValues(n.Attributes)})
+	}
+	for i, e := range graph.Edges {
+		element := edge{
+			ID:        fmt.Sprintf("%d", e.Index),
+			Source:    e.Tail.Name,
+			Target:    e.Head.Name,
+			AttValues: edgeClass.attValues(edgeAttributes[i]),
+		}
+		if weight, exists := e.Attributes["weight"]; exists && edgeClass.weight.IsSome() {
+			element.Weight = convert(weight, edgeClass.weight.Unwrap().Type)
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, element)
+	}
+
+	// Marshalling only fails on unsupported types, and document only holds
+	// strings
+	out, _ := xml.MarshalIndent(doc, "", "  ")
+	return xml.Header + string(out) + "\n"
+}
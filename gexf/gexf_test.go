@@ -0,0 +1,102 @@
+package gexf
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph deps {
+		label="Dependencies"
+		rankdir=LR
+		nodesep=0.5
+		a [label="A", width=2]
+		b [width=1.5, color=red]
+		a:out -> b [weight=3]
+		b -> a [weight=1, label="back"]
+	}`)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <meta>
+    <description>Dependencies</description>
+  </meta>
+  <graph defaultedgetype="directed" mode="static">
+    <attributes class="graph" mode="static">
+      <attribute id="0" title="nodesep" type="double">
+        <default>0.5</default>
+      </attribute>
+      <attribute id="1" title="rankdir" type="string">
+        <default>LR</default>
+      </attribute>
+    </attributes>
+    <attributes class="node" mode="static">
+      <attribute id="0" title="color" type="string"></attribute>
+      <attribute id="1" title="dotlabel" type="string"></attribute>
+      <attribute id="2" title="width" type="double"></attribute>
+    </attributes>
+    <attributes class="edge" mode="static">
+      <attribute id="0" title="dotlabel" type="string"></attribute>
+      <attribute id="1" title="tailport" type="string"></attribute>
+    </attributes>
+    <nodes>
+      <node id="a" label="a">
+        <attvalues>
+          <attvalue for="1" value="A"></attvalue>
+          <attvalue for="2" value="2"></attvalue>
+        </attvalues>
+      </node>
+      <node id="b" label="b">
+        <attvalues>
+          <attvalue for="0" value="red"></attvalue>
+          <attvalue for="2" value="1.5"></attvalue>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b" weight="3">
+        <attvalues>
+          <attvalue for="1" value="out"></attvalue>
+        </attvalues>
+      </edge>
+      <edge id="1" source="b" target="a" weight="1">
+        <attvalues>
+          <attvalue for="0" value="back"></attvalue>
+        </attvalues>
+      </edge>
+    </edges>
+  </graph>
+</gexf>
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportUndirected(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { a -- b [weight=heavy] }`)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <graph defaultedgetype="undirected" mode="static">
+    <attributes class="edge" mode="static">
+      <attribute id="0" title="dotweight" type="string"></attribute>
+    </attributes>
+    <nodes>
+      <node id="a" label="a"></node>
+      <node id="b" label="b"></node>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b">
+        <attvalues>
+          <attvalue for="0" value="heavy"></attvalue>
+        </attvalues>
+      </edge>
+    </edges>
+  </graph>
+</gexf>
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
@@ -0,0 +1,181 @@
+package gml
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// keyPattern matches the keys NetworkX accepts, which also allow the
+// underscore forbidden by the GML specification
+var keyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// reserved holds the keys GML gives a meaning to, by list
+var reserved = map[string]map[string]bool{
+	"graph": {"directed": true, "multigraph": true, "name": true, "node": true, "edge": true},
+	"node":  {"id": true, "label": true},
+	"edge":  {"source": true, "target": true, "key": true},
+}
+
+// RESERVED_PREFIX is prepended to the attributes named like a reserved key
+const RESERVED_PREFIX = "dot"
+
+func makeList(name string, attributes []parser.AttributeMap) []attribute.Column {
+	return attribute.InferColumns(attributes, sanitize, func(key string) bool {
+		return reserved[name][key]
+	}, RESERVED_PREFIX)
+}
+
+// sanitize replaces the characters not allowed in a GML key by underscores,
+// and prefixes the keys that do not start with a letter
+func sanitize(key string) string {
+	if keyPattern.MatchString(key) {
+		return key
+	}
+
+	var builder strings.Builder
+	if key == "" || !isLetter(rune(key[0])) {
+		builder.WriteString(RESERVED_PREFIX)
+	}
+	for _, char := range key {
+		if isLetter(char) || (char >= '0' && char <= '9') || char == '_' {
+			builder.WriteRune(char)
+		} else {
+			builder.WriteByte('_')
+		}
+	}
+	return builder.String()
+}
+
+func isLetter(char rune) bool {
+	return (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z')
+}
+
+type exporter struct {
+	graph   *model.Graph
+	builder strings.Builder
+}
+
+// Export writes a graph as GML, the format read by NetworkX and Gephi.
+//
+// Every node gets a numeric id, in declaration order, and its name as label.
+// The attributes of the graph, of the nodes and of the edges, as well as the
+// ports of the edges as tailport and headport, are written with the type
+// inferred from all the values of the attribute: an integer when they all
+// are integers, a real when they all are numbers and a string otherwise.
+// Attributes named like a GML key of the list that holds them, such as the
+// label of nodes, are prefixed with "dot"; in names that are not GML keys,
+// the characters other than ASCII letters, digits and underscores become
+// underscores, and "dot" is prepended when they do not start with a letter.
+// Subgraphs are left out. Graphs with parallel edges are
+// marked as multigraphs.
+func Export(graph parser.Graph) string {
+	return ExportModel(model.Resolve(graph))
+}
+
+func ExportModel(graph *model.Graph) string {
+	e := exporter{graph: graph}
+
+	nodeAttributes := make([]parser.AttributeMap, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		nodeAttributes[i] = node.Attributes
+	}
+	edgeAttributes := make([]parser.AttributeMap, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		edgeAttributes[i] = edge.AttributesWithPorts()
+	}
+	graphList := makeList("graph", []parser.AttributeMap{graph.Attributes})
+	nodeList := makeList("node", nodeAttributes)
+	edgeList := makeList("edge", edgeAttributes)
+
+	e.builder.WriteString("graph [\n")
+	if graph.IsDirect {
+		e.builder.WriteString("  directed 1\n")
+	} else {
+		e.builder.WriteString("  directed 0\n")
+	}
+	if hasParallelEdges(graph) {
+		e.builder.WriteString("  multigraph 1\n")
+	}
+	if graph.Name.IsSome() {
+		e.builder.WriteString("  name " + quote(graph.Name.Unwrap()) + "\n")
+	}
+	e.attributes("  ", graphList, graph.Attributes)
+
+	for _, node := range graph.Nodes {
+		e.builder.WriteString("  node [\n")
+		e.builder.WriteString(fmt.Sprintf("    id %d\n", node.Index))
+		e.builder.WriteString("    label " + quote(node.Name) + "\n")
+		e.attributes("    ", nodeList, node.Attributes)
+		e.builder.WriteString("  ]\n")
+	}
+
+	for i, edge := range graph.Edges {
+		e.builder.WriteString("  edge [\n")
+		e.builder.WriteString(fmt.Sprintf("    source %d\n", edge.Tail.Index))
+		e.builder.WriteString(fmt.Sprintf("    target %d\n", edge.Head.Index))
+		e.attributes("    ", edgeList, edgeAttributes[i])
+		e.builder.WriteString("  ]\n")
+	}
+	e.builder.WriteString("]\n")
+	return e.builder.String()
+}
+
+func hasParallelEdges(graph *model.Graph) bool {
+	seen := make(map[[2]int]bool)
+	for _, edge := range graph.Edges {
+		ends := [2]int{edge.Tail.Index, edge.Head.Index}
+		if !graph.IsDirect && ends[0] > ends[1] {
+			ends[0], ends[1] = ends[1], ends[0]
+		}
+		if seen[ends] {
+			return true
+		}
+		seen[ends] = true
+	}
+	return false
+}
+
+func (e *exporter) attributes(indent string, columns []attribute.Column, attributes parser.AttributeMap) {
+	for _, column := range columns {
+		if value, exists := attributes[column.Key]; exists {
+			e.builder.WriteString(indent + column.Name + " " + format(value, column.Type) + "\n")
+		}
+	}
+}
+
+func format(value string, valueType attribute.ValueType) string {
+	switch valueType {
+	case attribute.INT:
+		return attribute.FormatInt(attribute.ParseInt(value).Unwrap())
+	case attribute.DOUBLE:
+		// GML reals always have a decimal point
+		return attribute.FormatReal(attribute.ParseDouble(value).Unwrap())
+	default:
+		return quote(value)
+	}
+}
+
+// quote writes a GML string, where quotes, ampersands and characters outside
+// printable ASCII are written as character references
+func quote(value string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, char := range value {
+		switch {
+		case char == '"':
+			builder.WriteString("&quot;")
+		case char == '&':
+			builder.WriteString("&amp;")
+		case char < ' ' || char > '~':
+			builder.WriteString(fmt.Sprintf("&#%d;", char))
+		default:
+			builder.WriteRune(char)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
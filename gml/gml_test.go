@@ -0,0 +1,92 @@
+package gml
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph deps {
+		label="Dependencies"
+		a [label="A \"core\"", width=2]
+		b [width=1.5, _private=x, "font-size"=10]
+		a:out -> b [weight=3]
+		b -> a [weight=1]
+		b -> a [weight=2, label="é"]
+	}`)
+
+	expected := `graph [
+  directed 1
+  multigraph 1
+  name "deps"
+  label "Dependencies"
+  node [
+    id 0
+    label "a"
+    dotlabel "A &quot;core&quot;"
+    width 2.0
+  ]
+  node [
+    id 1
+    label "b"
+    dot_private "x"
+    font_size 10
+    width 1.5
+  ]
+  edge [
+    source 0
+    target 1
+    tailport "out"
+    weight 3
+  ]
+  edge [
+    source 1
+    target 0
+    weight 1
+  ]
+  edge [
+    source 1
+    target 0
+    label "&#233;"
+    weight 2
+  ]
+]
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportUndirected(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { a -- b [weight=x]; b -- c [weight=2] }`)
+
+	expected := `graph [
+  directed 0
+  node [
+    id 0
+    label "a"
+  ]
+  node [
+    id 1
+    label "b"
+  ]
+  node [
+    id 2
+    label "c"
+  ]
+  edge [
+    source 0
+    target 1
+    weight "x"
+  ]
+  edge [
+    source 1
+    target 2
+    weight "2"
+  ]
+]
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
@@ -0,0 +1,71 @@
+package graphdb
+
+import (
+	"dot-parser/alias"
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"fmt"
+	"strings"
+)
+
+// ExportCypher writes a graph as Cypher statements that can be run any
+// number of times: every node is merged on its label and its ID property,
+// every relationship on its endpoints, its type and its ordinal among the
+// parallel relationships of the same type, and then the other DOT
+// attributes are set as properties. Labels and types are read from the
+// configured attributes, which are not set as properties; the ports of the
+// edges are the tailport and headport properties. Properties are integers
+// or floats when all the values of the attribute are, strings otherwise.
+// Relationships of undirected graphs are merged without a direction.
+func ExportCypher(graph parser.Graph, options Options) string {
+	return ExportCypherModel(model.Resolve(graph), options)
+}
+
+func ExportCypherModel(graph *model.Graph, options Options) string {
+	nodes, relationships := prepare(graph, options)
+
+	var builder strings.Builder
+	for _, n := range nodes {
+		builder.WriteString(fmt.Sprintf("MERGE (n:%s {%s: %s})", name(n.label), name(options.IDProperty), quote(n.id)))
+		builder.WriteString(cypherSet("n", n.properties) + ";\n")
+	}
+
+	arrow := "->"
+	if !graph.IsDirect {
+		arrow = "-"
+	}
+	for _, r := range relationships {
+		builder.WriteString(fmt.Sprintf("MATCH (a:%s {%s: %s}), (b:%s {%s: %s}) ",
+			name(r.tail.label), name(options.IDProperty), quote(r.tail.id),
+			name(r.head.label), name(options.IDProperty), quote(r.head.id)))
+		builder.WriteString(fmt.Sprintf("MERGE (a)-[r:%s {%s: %d}]%s(b)", name(r.kind), ORDINAL_PROPERTY, r.ordinal, arrow))
+		builder.WriteString(cypherSet("r", r.properties) + ";\n")
+	}
+	return builder.String()
+}
+
+// name writes a label, a type or a property key, between backticks when it
+// is not an identifier
+func name(value string) string {
+	if alias.IsIdentifier(value) {
+		return value
+	}
+	return "`" + strings.ReplaceAll(value, "`", "``") + "`"
+}
+
+func cypherSet(variable string, properties []property) string {
+	if len(properties) == 0 {
+		return ""
+	}
+
+	assignments := make([]string, len(properties))
+	for i, p := range properties {
+		value := quote(p.value)
+		if p.valueType == attribute.INT || p.valueType == attribute.DOUBLE {
+			value = number(p)
+		}
+		assignments[i] = variable + "." + name(p.key) + " = " + value
+	}
+	return " SET " + strings.Join(assignments, ", ")
+}
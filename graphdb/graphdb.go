@@ -0,0 +1,147 @@
+package graphdb
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"strings"
+)
+
+type Options struct {
+	// LabelAttribute is the node attribute holding the label of a node, ""
+	// to give every node the default label
+	LabelAttribute string
+	DefaultLabel   string
+	// TypeAttribute is the edge attribute holding the type of a
+	// relationship, "" to give every relationship the default type
+	TypeAttribute string
+	DefaultType   string
+	// IDProperty is the node property holding the DOT ID
+	IDProperty string
+}
+
+func DefaultOptions() Options {
+	return Options{DefaultLabel: "Node", DefaultType: "EDGE", IDProperty: "id"}
+}
+
+// ORDINAL_PROPERTY is the relationship property telling apart the parallel
+// relationships of the same type, 0 for the first one
+const ORDINAL_PROPERTY = "ordinal"
+
+// RESERVED_PREFIX is prepended to the attributes named like the ID or the
+// ordinal property
+const RESERVED_PREFIX = "dot_"
+
+type property struct {
+	key       string
+	value     string
+	valueType attribute.ValueType
+}
+
+type node struct {
+	label      string
+	id         string
+	properties []property
+}
+
+type relationship struct {
+	tail       *node
+	head       *node
+	kind       string
+	ordinal    int
+	properties []property
+}
+
+// prepare splits the attributes of the nodes and of the edges in labels or
+// types and properties, whose types are inferred from all the values of
+// each attribute
+func prepare(graph *model.Graph, options Options) ([]*node, []*relationship) {
+	nodeAttributes := make([]parser.AttributeMap, len(graph.Nodes))
+	for i, n := range graph.Nodes {
+		nodeAttributes[i] = without(n.Attributes, options.LabelAttribute)
+	}
+	edgeAttributes := make([]parser.AttributeMap, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		edgeAttributes[i] = without(edge.AttributesWithPorts(), options.TypeAttribute)
+	}
+	nodeColumns := columns(nodeAttributes, options.IDProperty)
+	edgeColumns := columns(edgeAttributes, ORDINAL_PROPERTY)
+
+	nodes := make([]*node, len(graph.Nodes))
+	for i, n := range graph.Nodes {
+		label := options.DefaultLabel
+		if value, exists := n.Attributes[options.LabelAttribute]; exists && value != "" {
+			label = value
+		}
+		nodes[i] = &node{label: label, id: n.Name, properties: properties(nodeAttributes[i], nodeColumns)}
+	}
+
+	relationships := make([]*relationship, len(graph.Edges))
+	// ordinals counts the relationships with the same endpoints and type
+	ordinals := make(map[[3]string]int)
+	for i, edge := range graph.Edges {
+		kind := options.DefaultType
+		if value, exists := edge.Attributes[options.TypeAttribute]; exists && value != "" {
+			kind = value
+		}
+		tail, head := nodes[edge.Tail.Index], nodes[edge.Head.Index]
+		if !graph.IsDirect && tail.id > head.id {
+			tail, head = head, tail
+		}
+
+		key := [3]string{tail.id, head.id, kind}
+		relationships[i] = &relationship{
+			tail:       tail,
+			head:       head,
+			kind:       kind,
+			ordinal:    ordinals[key],
+			properties: properties(edgeAttributes[i], edgeColumns),
+		}
+		ordinals[key]++
+	}
+	return nodes, relationships
+}
+
+func without(attributes parser.AttributeMap, key string) parser.AttributeMap {
+	copied := make(parser.AttributeMap, len(attributes))
+	for name, value := range attributes {
+		if name != key {
+			copied[name] = value
+		}
+	}
+	return copied
+}
+
+// columns returns the properties of the attributes, renaming the one that
+// is named like the reserved property
+func columns(attributes []parser.AttributeMap, reserved string) []attribute.Column {
+	return attribute.InferColumns(attributes, nil, func(key string) bool {
+		return key == reserved
+	}, RESERVED_PREFIX)
+}
+
+// properties returns the attributes sorted by name
+func properties(attributes parser.AttributeMap, columns []attribute.Column) []property {
+	var properties []property
+	for _, column := range columns {
+		if value, exists := attributes[column.Key]; exists {
+			properties = append(properties, property{key: column.Name, value: value, valueType: column.Type})
+		}
+	}
+	return properties
+}
+
+// quote writes a string literal, valid both in Cypher and in Gremlin
+func quote(value string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "'", "\\'", "\n", "\\n", "\r", "\\r", "\t", "\\t")
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// number formats a numeric property, floats always have a decimal point so
+// that they are not read as integers
+func number(p property) string {
+	if p.valueType == attribute.INT {
+		return attribute.FormatInt(attribute.ParseInt(p.value).Unwrap())
+	}
+	return attribute.FormatReal(attribute.ParseDouble(p.value).Unwrap())
+}
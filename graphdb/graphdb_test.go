@@ -0,0 +1,60 @@
+package graphdb
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+const architecture = `digraph {
+	api [kind=Service, id=x, replicas=3]
+	"orders db" [kind="Data Store"]
+	cache
+	api -> "orders db" [rel=READS, weight=2]
+	api -> "orders db" [rel=READS, weight=0.5, label="it's slow"]
+	api:out -> cache
+}`
+
+func options() Options {
+	options := DefaultOptions()
+	options.LabelAttribute = "kind"
+	options.TypeAttribute = "rel"
+	return options
+}
+
+func TestExportCypher(t *testing.T) {
+	expected := "MERGE (n:Service {id: 'api'}) SET n.dot_id = 'x', n.replicas = 3;\n" +
+		"MERGE (n:`Data Store` {id: 'orders db'});\n" +
+		"MERGE (n:Node {id: 'cache'});\n" +
+		"MATCH (a:Service {id: 'api'}), (b:`Data Store` {id: 'orders db'}) MERGE (a)-[r:READS {ordinal: 0}]->(b) SET r.weight = 2.0;\n" +
+		"MATCH (a:Service {id: 'api'}), (b:`Data Store` {id: 'orders db'}) MERGE (a)-[r:READS {ordinal: 1}]->(b) SET r.label = 'it\\'s slow', r.weight = 0.5;\n" +
+		"MATCH (a:Service {id: 'api'}), (b:Node {id: 'cache'}) MERGE (a)-[r:EDGE {ordinal: 0}]->(b) SET r.tailport = 'out';\n"
+
+	if exported := ExportCypher(testutil.ParseGraph(t, architecture), options()); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportCypherUndirected(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { b -- a; a -- b }`)
+
+	expected := "MERGE (n:Node {id: 'b'});\n" +
+		"MERGE (n:Node {id: 'a'});\n" +
+		"MATCH (a:Node {id: 'a'}), (b:Node {id: 'b'}) MERGE (a)-[r:EDGE {ordinal: 0}]-(b);\n" +
+		"MATCH (a:Node {id: 'a'}), (b:Node {id: 'b'}) MERGE (a)-[r:EDGE {ordinal: 1}]-(b);\n"
+	if exported := ExportCypher(graph, DefaultOptions()); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportGremlin(t *testing.T) {
+	expected := "g.V().has('Service', 'id', 'api').fold().coalesce(unfold(), addV('Service').property('id', 'api')).property('dot_id', 'x').property('replicas', 3).iterate()\n" +
+		"g.V().has('Data Store', 'id', 'orders db').fold().coalesce(unfold(), addV('Data Store').property('id', 'orders db')).iterate()\n" +
+		"g.V().has('Node', 'id', 'cache').fold().coalesce(unfold(), addV('Node').property('id', 'cache')).iterate()\n" +
+		"g.V().has('Service', 'id', 'api').as('a').V().has('Data Store', 'id', 'orders db').coalesce(inE('READS').where(outV().as('a')).has('ordinal', 0), addE('READS').from('a').property('ordinal', 0)).property('weight', 2.0d).iterate()\n" +
+		"g.V().has('Service', 'id', 'api').as('a').V().has('Data Store', 'id', 'orders db').coalesce(inE('READS').where(outV().as('a')).has('ordinal', 1), addE('READS').from('a').property('ordinal', 1)).property('label', 'it\\'s slow').property('weight', 0.5d).iterate()\n" +
+		"g.V().has('Service', 'id', 'api').as('a').V().has('Node', 'id', 'cache').coalesce(inE('EDGE').where(outV().as('a')).has('ordinal', 0), addE('EDGE').from('a').property('ordinal', 0)).property('tailport', 'out').iterate()\n"
+
+	if exported := ExportGremlin(testutil.ParseGraph(t, architecture), options()); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
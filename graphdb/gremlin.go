@@ -0,0 +1,56 @@
+package graphdb
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"fmt"
+	"strings"
+)
+
+// ExportGremlin writes a graph as Gremlin traversals that can be run any
+// number of times, upserting the same vertices, edges and properties
+// ExportCypher merges, one traversal per line. Gremlin edges always have a
+// direction, edges of undirected graphs go from the endpoint whose ID comes
+// first. Float properties are written as doubles.
+func ExportGremlin(graph parser.Graph, options Options) string {
+	return ExportGremlinModel(model.Resolve(graph), options)
+}
+
+func ExportGremlinModel(graph *model.Graph, options Options) string {
+	nodes, relationships := prepare(graph, options)
+
+	var builder strings.Builder
+	for _, n := range nodes {
+		builder.WriteString(fmt.Sprintf("g.V().has(%s, %s, %s).fold().coalesce(unfold(), addV(%s).property(%s, %s))",
+			quote(n.label), quote(options.IDProperty), quote(n.id),
+			quote(n.label), quote(options.IDProperty), quote(n.id)))
+		builder.WriteString(gremlinProperties(n.properties) + ".iterate()\n")
+	}
+
+	for _, r := range relationships {
+		builder.WriteString(fmt.Sprintf("g.V().has(%s, %s, %s).as('a').V().has(%s, %s, %s)",
+			quote(r.tail.label), quote(options.IDProperty), quote(r.tail.id),
+			quote(r.head.label), quote(options.IDProperty), quote(r.head.id)))
+		builder.WriteString(fmt.Sprintf(".coalesce(inE(%s).where(outV().as('a')).has(%s, %d), addE(%s).from('a').property(%s, %d))",
+			quote(r.kind), quote(ORDINAL_PROPERTY), r.ordinal,
+			quote(r.kind), quote(ORDINAL_PROPERTY), r.ordinal))
+		builder.WriteString(gremlinProperties(r.properties) + ".iterate()\n")
+	}
+	return builder.String()
+}
+
+func gremlinProperties(properties []property) string {
+	var builder strings.Builder
+	for _, p := range properties {
+		value := quote(p.value)
+		switch p.valueType {
+		case attribute.INT:
+			value = number(p)
+		case attribute.DOUBLE:
+			value = number(p) + "d"
+		}
+		builder.WriteString(".property(" + quote(p.key) + ", " + value + ")")
+	}
+	return builder.String()
+}
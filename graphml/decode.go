@@ -0,0 +1,188 @@
+package graphml
+
+import (
+	"dot-parser/builder"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type DecodeError struct {
+	Message string
+}
+
+func (err *DecodeError) Error() string {
+	return fmt.Sprintf("GraphML error: %s", err.Message)
+}
+
+type decoder struct {
+	keys map[string]key
+}
+
+// Decode reads a GraphML document. Nodes holding a nested graph become
+// subgraphs named after the id of the nested graph, anonymous when it has
+// none, and nodes and edges are declared in the graph that holds them. Data
+// whose key has no attr.name, such as the graphics of yEd, is skipped, and
+// the defaults of node and edge keys become node and edge default
+// statements.
+func Decode(reader io.Reader) Result[parser.Graph] {
+	var doc document
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return Err[parser.Graph](&DecodeError{Message: err.Error()})
+	}
+
+	d := decoder{keys: make(map[string]key, len(doc.Keys))}
+	for _, key := range doc.Keys {
+		d.keys[key.ID] = key
+	}
+
+	var b *builder.Builder
+	if doc.Graph.EdgeDefault == "undirected" {
+		b = builder.Graph()
+	} else {
+		b = builder.Digraph()
+	}
+	if doc.Graph.ID != "" {
+		b.Name(doc.Graph.ID)
+	}
+
+	attributes, err := d.attributes(doc.Graph.Data)
+	if err != nil {
+		return Err[parser.Graph](err)
+	}
+	if attributes[strictKey] == "true" {
+		b.Strict()
+		delete(attributes, strictKey)
+	}
+	defaults := d.defaults()
+	for name, value := range defaults[GRAPH_DOMAIN] {
+		if _, exists := attributes[name]; !exists {
+			attributes[name] = value
+		}
+	}
+	if len(defaults[NODE_DOMAIN]) > 0 {
+		b.NodeDefaults(defaults[NODE_DOMAIN])
+	}
+	if len(defaults[EDGE_DOMAIN]) > 0 {
+		b.EdgeDefaults(defaults[EDGE_DOMAIN])
+	}
+	for _, name := range attributes.Keys() {
+		b.Attr(name, attributes[name])
+	}
+
+	if err := d.fill(b, doc.Graph); err != nil {
+		return Err[parser.Graph](err)
+	}
+	return Ok(b.Build())
+}
+
+// defaults returns the default values of the keys by domain, keys for all
+// domains apply to each of them
+func (d *decoder) defaults() map[string]parser.AttributeMap {
+	defaults := make(map[string]parser.AttributeMap)
+	ids := make([]string, 0, len(d.keys))
+	for id := range d.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		key := d.keys[id]
+		if key.Default == nil || key.Name == "" {
+			continue
+		}
+
+		domains := []string{key.For}
+		if key.For == ALL_DOMAIN {
+			domains = []string{GRAPH_DOMAIN, NODE_DOMAIN, EDGE_DOMAIN}
+		}
+		for _, domain := range domains {
+			if defaults[domain] == nil {
+				defaults[domain] = parser.AttributeMap{}
+			}
+			defaults[domain][key.Name] = *key.Default
+		}
+	}
+	return defaults
+}
+
+func (d *decoder) attributes(elements []data) (parser.AttributeMap, error) {
+	attributes := parser.AttributeMap{}
+	for _, element := range elements {
+		key, exists := d.keys[element.Key]
+		if !exists {
+			return nil, &DecodeError{Message: fmt.Sprintf("undeclared key %q", element.Key)}
+		}
+		if key.Name != "" {
+			attributes[key.Name] = element.Value
+		}
+	}
+	return attributes, nil
+}
+
+// fill declares the nodes, subgraphs and edges of a graph element
+func (d *decoder) fill(b *builder.Builder, g graph) error {
+	for _, n := range g.Nodes {
+		attributes, err := d.attributes(n.Data)
+		if err != nil {
+			return err
+		}
+
+		if n.Graph == nil {
+			if len(attributes) > 0 {
+				b.Node(n.ID, attributes)
+			} else {
+				b.Node(n.ID)
+			}
+			continue
+		}
+
+		nested, err := d.attributes(n.Graph.Data)
+		if err != nil {
+			return err
+		}
+		for name, value := range nested {
+			attributes[name] = value
+		}
+
+		var fillErr error
+		fill := func(sub *builder.Builder) {
+			for _, name := range attributes.Keys() {
+				sub.Attr(name, attributes[name])
+			}
+			fillErr = d.fill(sub, *n.Graph)
+		}
+		if n.Graph.ID == "" {
+			b.AnonymousSubgraph(fill)
+		} else {
+			b.Subgraph(n.Graph.ID, fill)
+		}
+		if fillErr != nil {
+			return fillErr
+		}
+	}
+
+	for _, e := range g.Edges {
+		attributes, err := d.attributes(e.Data)
+		if err != nil {
+			return err
+		}
+
+		tail, head := builder.ID(e.Source), builder.ID(e.Target)
+		if e.SourcePort != "" {
+			tail = builder.Port(e.Source, e.SourcePort)
+		}
+		if e.TargetPort != "" {
+			head = builder.Port(e.Target, e.TargetPort)
+		}
+		if len(attributes) > 0 {
+			b.EdgeID(tail, head, attributes)
+		} else {
+			b.EdgeID(tail, head)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package graphml
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type keyName struct {
+	domain string
+	name   string
+}
+
+type encoder struct {
+	graph *model.Graph
+	keys  map[keyName]string
+	// placement maps a node to the subgraph it is declared in, nodes that are
+	// not in a subgraph are declared in the root graph
+	placement  map[*model.Node]*model.Subgraph
+	containers map[*model.Subgraph]string
+	ports      map[*model.Node][]string
+}
+
+// Encode writes a graph as a GraphML document. Every attribute becomes a
+// <data> element, declared by a <key> for the graph, node or edge domain;
+// keys of numeric attributes are typed int or double when all of their
+// values are numerals. Subgraphs become nodes holding a nested <graph> named
+// after them, whose id is "subgraph:<name>" and which declares the nodes of
+// the subgraph. GraphML nests nodes in exactly one graph, so a node that is
+// in several sibling subgraphs is only declared in the first one. Ports are
+// declared on their nodes and referenced by sourceport and targetport.
+func Encode(graph parser.Graph) string {
+	return EncodeModel(model.Resolve(graph))
+}
+
+func EncodeModel(graph *model.Graph) string {
+	e := encoder{
+		graph:      graph,
+		keys:       make(map[keyName]string),
+		containers: make(map[*model.Subgraph]string),
+		ports:      make(map[*model.Node][]string),
+	}
+
+	doc := document{Xmlns: namespace, Keys: e.declareKeys()}
+	e.placement = graph.Placement(func(*model.Subgraph) bool { return true })
+	e.collectPorts()
+
+	edgeDefault := "undirected"
+	if graph.IsDirect {
+		edgeDefault = "directed"
+	}
+
+	var topLevelEdges []*model.Edge
+	owned := make(map[*model.Edge]bool)
+	var own func([]*model.Subgraph)
+	own = func(subgraphs []*model.Subgraph) {
+		for _, subgraph := range subgraphs {
+			for _, edge := range subgraph.Edges {
+				owned[edge] = true
+			}
+			own(subgraph.Subgraphs)
+		}
+	}
+	own(graph.Subgraphs)
+	for _, edge := range graph.Edges {
+		if !owned[edge] {
+			topLevelEdges = append(topLevelEdges, edge)
+		}
+	}
+
+	attributes := e.data(GRAPH_DOMAIN, graph.Attributes)
+	if _, exists := graph.Attributes[strictKey]; graph.IsStrict && !exists {
+		attributes = append([]data{{Key: e.keys[keyName{GRAPH_DOMAIN, strictKey}], Value: "true"}}, attributes...)
+	}
+	doc.Graph = makeGraph(graph.Name.OrElse(""), edgeDefault, attributes, e.nodes(nil), e.edges(topLevelEdges))
+	doc.Graph.Nodes = append(doc.Graph.Nodes, e.subgraphs(graph.Subgraphs, edgeDefault)...)
+
+	// Marshalling only fails on unsupported types, and document only holds
+	// strings
+	out, _ := xml.MarshalIndent(doc, "", "  ")
+	return xml.Header + string(out) + "\n"
+}
+
+func makeGraph(id string, edgeDefault string, attributes []data, nodes []node, edges []edge) graph {
+	return graph{ID: id, EdgeDefault: edgeDefault, Data: attributes, Nodes: nodes, Edges: edges}
+}
+
+// declareKeys declares a key for every attribute name used in each domain
+func (e *encoder) declareKeys() []key {
+	values := make(map[keyName][]string)
+	addAll := func(domain string, attributes parser.AttributeMap) {
+		for name, value := range attributes {
+			values[keyName{domain, name}] = append(values[keyName{domain, name}], value)
+		}
+	}
+
+	addAll(GRAPH_DOMAIN, e.graph.Attributes)
+	var addSubgraphs func([]*model.Subgraph)
+	addSubgraphs = func(subgraphs []*model.Subgraph) {
+		for _, subgraph := range subgraphs {
+			addAll(GRAPH_DOMAIN, subgraph.Attributes)
+			addSubgraphs(subgraph.Subgraphs)
+		}
+	}
+	addSubgraphs(e.graph.Subgraphs)
+	for _, node := range e.graph.Nodes {
+		addAll(NODE_DOMAIN, node.Attributes)
+	}
+	for _, edge := range e.graph.Edges {
+		addAll(EDGE_DOMAIN, edge.Attributes)
+	}
+
+	names := make([]keyName, 0, len(values)+1)
+	for name := range values {
+		names = append(names, name)
+	}
+	if e.graph.IsStrict {
+		if _, exists := values[keyName{GRAPH_DOMAIN, strictKey}]; !exists {
+			names = append(names, keyName{GRAPH_DOMAIN, strictKey})
+		}
+	}
+
+	domainOrder := map[string]int{GRAPH_DOMAIN: 0, NODE_DOMAIN: 1, EDGE_DOMAIN: 2}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].domain != names[j].domain {
+			return domainOrder[names[i].domain] < domainOrder[names[j].domain]
+		}
+		return names[i].name < names[j].name
+	})
+
+	keys := make([]key, len(names))
+	for i, name := range names {
+		id := fmt.Sprintf("d%d", i)
+		e.keys[name] = id
+
+		keyType := keyType(name.name, values[name])
+		if name == (keyName{GRAPH_DOMAIN, strictKey}) && e.graph.IsStrict {
+			keyType = "boolean"
+		}
+		keys[i] = key{ID: id, For: name.domain, Name: name.name, Type: keyType}
+	}
+	return keys
+}
+
+// keyType returns int or double for the attributes of the catalogue whose
+// types are numeric, when every value is a numeral of one of those types,
+// and string otherwise
+func keyType(name string, values []string) string {
+	definition := attribute.Lookup(name)
+	if definition.IsNone() {
+		return "string"
+	}
+
+	var allowsInt, allowsDouble bool
+	for _, valueType := range definition.Unwrap().Types {
+		switch valueType {
+		case attribute.INT:
+			allowsInt = true
+		case attribute.DOUBLE:
+			allowsDouble = true
+		default:
+			return "string"
+		}
+	}
+
+	allInts, allDoubles := true, true
+	for _, value := range values {
+		if _, err := strconv.Atoi(value); err != nil {
+			allInts = false
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			allDoubles = false
+		}
+	}
+
+	switch {
+	case allowsInt && allInts:
+		return "int"
+	case allowsDouble && allDoubles:
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+func (e *encoder) data(domain string, attributes parser.AttributeMap) []data {
+	names := attributes.Keys()
+	out := make([]data, len(names))
+	for i, name := range names {
+		out[i] = data{Key: e.keys[keyName{domain, name}], Value: attributes[name]}
+	}
+	return out
+}
+
+func (e *encoder) collectPorts() {
+	seen := make(map[*model.Node]map[string]bool)
+	add := func(node *model.Node, port string) {
+		if seen[node] == nil {
+			seen[node] = make(map[string]bool)
+		}
+		if !seen[node][port] {
+			seen[node][port] = true
+			e.ports[node] = append(e.ports[node], port)
+		}
+	}
+
+	for _, edge := range e.graph.Edges {
+		if edge.TailPort.IsSome() {
+			add(edge.Tail, edge.TailPort.Unwrap())
+		}
+		if edge.HeadPort.IsSome() {
+			add(edge.Head, edge.HeadPort.Unwrap())
+		}
+	}
+	for _, ports := range e.ports {
+		sort.Strings(ports)
+	}
+}
+
+// nodes returns the nodes declared in subgraph, or in the root graph when
+// subgraph is nil
+func (e *encoder) nodes(subgraph *model.Subgraph) []node {
+	var nodes []node
+	for _, n := range e.graph.Nodes {
+		if e.placement[n] != subgraph {
+			continue
+		}
+
+		element := node{ID: n.Name, Data: e.data(NODE_DOMAIN, n.Attributes)}
+		for _, name := range e.ports[n] {
+			element.Ports = append(element.Ports, port{Name: name})
+		}
+		nodes = append(nodes, element)
+	}
+	return nodes
+}
+
+func (e *encoder) edges(edges []*model.Edge) []edge {
+	out := make([]edge, len(edges))
+	for i, ed := range edges {
+		out[i] = edge{
+			ID:         fmt.Sprintf("e%d", ed.Index),
+			Source:     ed.Tail.Name,
+			Target:     ed.Head.Name,
+			SourcePort: ed.TailPort.OrElse(""),
+			TargetPort: ed.HeadPort.OrElse(""),
+			Data:       e.data(EDGE_DOMAIN, ed.Attributes),
+		}
+	}
+	return out
+}
+
+// containerID returns an ID for the node holding a subgraph that is not the
+// name of a node
+func (e *encoder) containerID(subgraph *model.Subgraph, anonymous int) string {
+	id := "subgraph:" + subgraph.Name.OrElse(fmt.Sprintf("%%%d", anonymous))
+	for e.graph.Node(id).IsSome() {
+		id += "'"
+	}
+	return id
+}
+
+func (e *encoder) subgraphs(subgraphs []*model.Subgraph, edgeDefault string) []node {
+	var containers []node
+	for _, subgraph := range subgraphs {
+		id := e.containerID(subgraph, len(e.containers))
+		e.containers[subgraph] = id
+
+		nested := makeGraph(subgraph.Name.OrElse(""), edgeDefault, e.data(GRAPH_DOMAIN, subgraph.Attributes), e.nodes(subgraph), e.edges(subgraph.Edges))
+		nested.Nodes = append(nested.Nodes, e.subgraphs(subgraph.Subgraphs, edgeDefault)...)
+		containers = append(containers, node{ID: id, Graph: &nested})
+	}
+	return containers
+}
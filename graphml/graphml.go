@@ -0,0 +1,65 @@
+package graphml
+
+import "encoding/xml"
+
+const namespace = "http://graphml.graphdrawing.org/xmlns"
+
+// Key domains
+const (
+	GRAPH_DOMAIN = "graph"
+	NODE_DOMAIN  = "node"
+	EDGE_DOMAIN  = "edge"
+	ALL_DOMAIN   = "all"
+)
+
+// strictKey is the name of the graph key recording that a graph is strict,
+// which GraphML cannot express
+const strictKey = "strict"
+
+type document struct {
+	XMLName xml.Name `xml:"graphml"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Keys    []key    `xml:"key"`
+	Graph   graph    `xml:"graph"`
+}
+
+type key struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr,omitempty"`
+	Type    string  `xml:"attr.type,attr,omitempty"`
+	Default *string `xml:"default"`
+}
+
+type graph struct {
+	ID          string `xml:"id,attr,omitempty"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Data        []data `xml:"data"`
+	Nodes       []node `xml:"node"`
+	Edges       []edge `xml:"edge"`
+}
+
+type node struct {
+	ID    string `xml:"id,attr"`
+	Data  []data `xml:"data"`
+	Ports []port `xml:"port"`
+	Graph *graph `xml:"graph"`
+}
+
+type port struct {
+	Name string `xml:"name,attr"`
+}
+
+type edge struct {
+	ID         string `xml:"id,attr,omitempty"`
+	Source     string `xml:"source,attr"`
+	Target     string `xml:"target,attr"`
+	SourcePort string `xml:"sourceport,attr,omitempty"`
+	TargetPort string `xml:"targetport,attr,omitempty"`
+	Data       []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
@@ -0,0 +1,100 @@
+package graphml
+
+import (
+	"dot-parser/builder"
+	"dot-parser/diff"
+	"dot-parser/internal/testutil"
+	"dot-parser/parser"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	expected, err := os.ReadFile("testdata/clusters.graphml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if encoded := Encode(testutil.ReadGraph(t, "testdata/clusters.gv")); encoded != string(expected) {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, encoded)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	expected := testutil.ReadGraph(t, "testdata/clusters.gv")
+	decoded := testutil.DecodeFile(t, "testdata/clusters.graphml", Decode)
+
+	if !diff.Equal(expected, decoded) {
+		t.Fatalf("Expected decoded graph to be equal, got changes:\n%s", diff.Compare(expected, decoded))
+	}
+}
+
+func TestDecodeYEd(t *testing.T) {
+	expected := builder.Digraph().Name("G").
+		EdgeDefaults(parser.AttributeMap{"weight": "1.0"}).
+		Node("n0", parser.AttributeMap{"description": "entry point"}).
+		Subgraph("n1:", func(sub *builder.Builder) {
+			sub.Attr("description", "group").Node("n1::n0")
+		}).
+		Edge("n0", "n1::n0", parser.AttributeMap{"weight": "2.5"}).
+		Build()
+
+	if decoded := testutil.DecodeFile(t, "testdata/yed.graphml", Decode); !diff.Equal(expected, decoded) {
+		t.Fatalf("Expected decoded graph to be equal, got changes:\n%s", diff.Compare(expected, decoded))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	graph := builder.Graph().
+		Attr("label", "<multi>\n\"line\" & more").
+		Path([]string{"a", "subgraph:x", "c"}, parser.AttributeMap{"penwidth": "2"}).
+		EdgeID(builder.Port("a", "n"), builder.Port("c", "s")).
+		Subgraph("x", func(sub *builder.Builder) {
+			sub.Node("d", parser.AttributeMap{"width": "wide"})
+		}).
+		Build()
+
+	decoded := Decode(strings.NewReader(Encode(graph)))
+	if decoded.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", decoded.UnwrapErr())
+	}
+	if !diff.Equal(graph, decoded.Unwrap()) {
+		t.Fatalf("Expected decoded graph to be equal, got changes:\n%s", diff.Compare(graph, decoded.Unwrap()))
+	}
+}
+
+func TestKeyType(t *testing.T) {
+	types := []struct {
+		name     string
+		values   []string
+		expected string
+	}{
+		{"minlen", []string{"1", "2"}, "int"},
+		{"weight", []string{"1", "2"}, "int"},
+		{"weight", []string{"1", "0.5"}, "double"},
+		{"width", []string{"1"}, "double"},
+		{"width", []string{"1", "wide"}, "string"},
+		{"label", []string{"1"}, "string"},
+		{"unknown", []string{"1"}, "string"},
+	}
+
+	for _, entry := range types {
+		if actual := keyType(entry.name, entry.values); actual != entry.expected {
+			t.Errorf("Expected %s for %s %v, got %s", entry.expected, entry.name, entry.values, actual)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	documents := []string{
+		`<graphml><graph>`,
+		`<graphml><graph edgedefault="directed"><node id="a"><data key="missing">x</data></node></graph></graphml>`,
+	}
+
+	for _, document := range documents {
+		if res := Decode(strings.NewReader(document)); res.IsOk() {
+			t.Errorf("Expected error for %s", document)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package gvjson
+
+import (
+	"dot-parser/builder"
+	"dot-parser/option"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type DecodeError struct {
+	Message string
+}
+
+func (err *DecodeError) Error() string {
+	return fmt.Sprintf("Graphviz JSON error: %s", err.Message)
+}
+
+func makeDecodeError(format string, args ...interface{}) Result[parser.Graph] {
+	return Err[parser.Graph](&DecodeError{Message: fmt.Sprintf(format, args...)})
+}
+
+type rawObject map[string]json.RawMessage
+
+// members read by the decoder rather than as attributes
+var reserved = map[string]bool{
+	"name":      true,
+	"directed":  true,
+	"strict":    true,
+	"objects":   true,
+	"edges":     true,
+	"nodes":     true,
+	"subgraphs": true,
+	"tail":      true,
+	"head":      true,
+}
+
+func (o rawObject) decode(key string, target interface{}) error {
+	raw, exists := o[key]
+	if !exists {
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("invalid %q: %s", key, err)
+	}
+	return nil
+}
+
+// attributes returns the string members of an object, skipping the ones read
+// by the decoder and the ones starting with '_', such as _gvid and the xdot
+// drawing operations of the json format
+func (o rawObject) attributes() parser.AttributeMap {
+	attributes := parser.AttributeMap{}
+	for key, raw := range o {
+		if reserved[key] || strings.HasPrefix(key, "_") {
+			continue
+		}
+		var value string
+		if json.Unmarshal(raw, &value) == nil {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
+
+type subgraph struct {
+	name       string
+	attributes parser.AttributeMap
+	subgraphs  []int
+	nodes      []int
+	edges      []int
+}
+
+type decoder struct {
+	subgraphs []subgraph
+	nodes     map[int]string
+	edges     []parser.Edge
+	// edgeIndex maps the _gvid of an edge to its index in edges
+	edgeIndex map[int]int
+	// owner maps an edge index to the innermost subgraph declaring it
+	owner map[int]int
+	added map[int]bool
+}
+
+// Decode reads a graph in the json or json0 schema of Graphviz. Nodes are
+// declared first with their attributes, subgraphs list the nodes that are
+// not in one of their nested subgraphs and edges are declared in the
+// innermost subgraph that contains them. Names starting with '%' are the
+// ones Graphviz gives to anonymous graphs and subgraphs. Layout and
+// rendering attributes are kept as attributes, xdot drawing operations are
+// skipped.
+func Decode(reader io.Reader) Result[parser.Graph] {
+	var root rawObject
+	if err := json.NewDecoder(reader).Decode(&root); err != nil {
+		return makeDecodeError("%s", err)
+	}
+
+	var name string
+	var isDirect, isStrict bool
+	var subgraphCount int
+	var objects, edges []rawObject
+	for key, target := range map[string]interface{}{
+		"name":          &name,
+		"directed":      &isDirect,
+		"strict":        &isStrict,
+		"_subgraph_cnt": &subgraphCount,
+		"objects":       &objects,
+		"edges":         &edges,
+	} {
+		if err := root.decode(key, target); err != nil {
+			return makeDecodeError("graph: %s", err)
+		}
+	}
+	if subgraphCount < 0 || subgraphCount > len(objects) {
+		return makeDecodeError("_subgraph_cnt %d out of range", subgraphCount)
+	}
+
+	d := decoder{
+		subgraphs: make([]subgraph, subgraphCount),
+		nodes:     make(map[int]string),
+		edgeIndex: make(map[int]int),
+		owner:     make(map[int]int),
+		added:     make(map[int]bool),
+	}
+
+	var nodeGvids []int
+	nodeAttributes := make(map[int]parser.AttributeMap)
+	subgraphDecoded := make([]bool, subgraphCount)
+	for i, object := range objects {
+		gvid := i
+		var sub subgraph
+		for key, target := range map[string]interface{}{
+			"_gvid":     &gvid,
+			"name":      &sub.name,
+			"subgraphs": &sub.subgraphs,
+			"nodes":     &sub.nodes,
+			"edges":     &sub.edges,
+		} {
+			if err := object.decode(key, target); err != nil {
+				return makeDecodeError("object %d: %s", i, err)
+			}
+		}
+		if gvid < 0 {
+			return makeDecodeError("object %d: invalid _gvid %d", i, gvid)
+		}
+
+		if gvid < subgraphCount {
+			if subgraphDecoded[gvid] {
+				return makeDecodeError("object %d: repeated _gvid %d", i, gvid)
+			}
+			subgraphDecoded[gvid] = true
+			sub.attributes = object.attributes()
+			d.subgraphs[gvid] = sub
+		} else {
+			if _, exists := d.nodes[gvid]; exists {
+				return makeDecodeError("object %d: repeated _gvid %d", i, gvid)
+			}
+			d.nodes[gvid] = sub.name
+			nodeGvids = append(nodeGvids, gvid)
+			nodeAttributes[gvid] = object.attributes()
+		}
+	}
+	sort.Ints(nodeGvids)
+
+	for i, object := range edges {
+		gvid := i
+		if err := object.decode("_gvid", &gvid); err != nil {
+			return makeDecodeError("edge %d: %s", i, err)
+		}
+		if _, exists := d.edgeIndex[gvid]; exists {
+			return makeDecodeError("edge %d: repeated _gvid %d", i, gvid)
+		}
+		d.edgeIndex[gvid] = i
+
+		var tail, head int
+		if err := object.decode("tail", &tail); err != nil {
+			return makeDecodeError("edge %d: %s", i, err)
+		}
+		if err := object.decode("head", &head); err != nil {
+			return makeDecodeError("edge %d: %s", i, err)
+		}
+		for _, gvid := range []int{tail, head} {
+			if _, exists := d.nodes[gvid]; !exists {
+				return makeDecodeError("edge %d: no node with _gvid %d", i, gvid)
+			}
+		}
+
+		attributes := object.attributes()
+		edge := parser.Edge{
+			Lnode: builder.ID(d.nodes[tail]),
+			Rnode: builder.ID(d.nodes[head]),
+		}
+		if port, exists := attributes["tailport"]; exists {
+			edge.Lnode.Port = option.Some(port)
+			delete(attributes, "tailport")
+		}
+		if port, exists := attributes["headport"]; exists {
+			edge.Rnode.Port = option.Some(port)
+			delete(attributes, "headport")
+		}
+		if len(attributes) > 0 {
+			edge.Attributes = []parser.AttributeMap{attributes}
+		}
+		d.edges = append(d.edges, edge)
+	}
+
+	if err := d.validate(); err != nil {
+		return Err[parser.Graph](err)
+	}
+
+	var b *builder.Builder
+	if isDirect {
+		b = builder.Digraph()
+	} else {
+		b = builder.Graph()
+	}
+	if isStrict {
+		b.Strict()
+	}
+	if name != "" && !strings.HasPrefix(name, "%") {
+		b.Name(name)
+	}
+
+	attributes := root.attributes()
+	for _, key := range attributes.Keys() {
+		b.Attr(key, attributes[key])
+	}
+	for _, gvid := range nodeGvids {
+		if len(nodeAttributes[gvid]) > 0 {
+			b.Node(d.nodes[gvid], nodeAttributes[gvid])
+		} else {
+			b.Node(d.nodes[gvid])
+		}
+	}
+
+	var topLevel []int
+	isChild := make(map[int]bool)
+	for _, sub := range d.subgraphs {
+		for _, child := range sub.subgraphs {
+			isChild[child] = true
+		}
+	}
+	for gvid := range d.subgraphs {
+		if !isChild[gvid] {
+			topLevel = append(topLevel, gvid)
+			d.assignEdges(gvid)
+		}
+	}
+
+	for index := range d.edges {
+		if _, owned := d.owner[index]; !owned {
+			b.EdgeID(d.edges[index].Lnode, d.edges[index].Rnode, d.edges[index].Attributes...)
+		}
+	}
+	for _, gvid := range topLevel {
+		d.addSubgraph(b, gvid)
+	}
+
+	return Ok(b.Build())
+}
+
+func (d *decoder) validate() error {
+	for gvid, sub := range d.subgraphs {
+		for _, child := range sub.subgraphs {
+			if child < 0 || child >= len(d.subgraphs) || child == gvid {
+				return &DecodeError{Message: fmt.Sprintf("subgraph %d: invalid subgraph %d", gvid, child)}
+			}
+		}
+		for _, node := range sub.nodes {
+			if _, exists := d.nodes[node]; !exists {
+				return &DecodeError{Message: fmt.Sprintf("subgraph %d: no node with _gvid %d", gvid, node)}
+			}
+		}
+		for _, edge := range sub.edges {
+			if _, exists := d.edgeIndex[edge]; !exists {
+				return &DecodeError{Message: fmt.Sprintf("subgraph %d: no edge with _gvid %d", gvid, edge)}
+			}
+		}
+	}
+	return d.checkCycles()
+}
+
+// checkCycles reports a subgraph that is nested in itself through its
+// subgraphs
+func (d *decoder) checkCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(d.subgraphs))
+	var visit func(gvid int) error
+	visit = func(gvid int) error {
+		state[gvid] = visiting
+		for _, child := range d.subgraphs[gvid].subgraphs {
+			switch state[child] {
+			case visiting:
+				return &DecodeError{Message: fmt.Sprintf("subgraph %d: cyclic subgraph %d", gvid, child)}
+			case unvisited:
+				if err := visit(child); err != nil {
+					return err
+				}
+			}
+		}
+		state[gvid] = done
+		return nil
+	}
+
+	for gvid := range d.subgraphs {
+		if state[gvid] == unvisited {
+			if err := visit(gvid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// assignEdges makes each subgraph the owner of its edges after its parent
+// did, so that edges end up in the innermost subgraph
+func (d *decoder) assignEdges(gvid int) {
+	visited := make(map[int]bool)
+	var visit func(int)
+	visit = func(gvid int) {
+		if visited[gvid] {
+			return
+		}
+		visited[gvid] = true
+		for _, edge := range d.subgraphs[gvid].edges {
+			d.owner[d.edgeIndex[edge]] = gvid
+		}
+		for _, child := range d.subgraphs[gvid].subgraphs {
+			visit(child)
+		}
+	}
+	visit(gvid)
+}
+
+func (d *decoder) addSubgraph(b *builder.Builder, gvid int) {
+	if d.added[gvid] {
+		return
+	}
+	d.added[gvid] = true
+	sub := d.subgraphs[gvid]
+
+	fill := func(b *builder.Builder) {
+		for _, key := range sub.attributes.Keys() {
+			b.Attr(key, sub.attributes[key])
+		}
+
+		nested := make(map[int]bool)
+		for _, child := range sub.subgraphs {
+			for _, node := range d.subgraphs[child].nodes {
+				nested[node] = true
+			}
+		}
+		for _, node := range sub.nodes {
+			if !nested[node] {
+				b.Node(d.nodes[node])
+			}
+		}
+
+		for _, edge := range sub.edges {
+			if index := d.edgeIndex[edge]; d.owner[index] == gvid {
+				b.EdgeID(d.edges[index].Lnode, d.edges[index].Rnode, d.edges[index].Attributes...)
+			}
+		}
+		for _, child := range sub.subgraphs {
+			d.addSubgraph(b, child)
+		}
+	}
+
+	if sub.name == "" || strings.HasPrefix(sub.name, "%") {
+		b.AnonymousSubgraph(fill)
+	} else {
+		b.Subgraph(sub.name, fill)
+	}
+}
@@ -0,0 +1,161 @@
+package gvjson
+
+import (
+	"bytes"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// object is a JSON object whose members are written in order
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, err := json.Marshal(member.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func withAttributes(o object, attributes parser.AttributeMap) object {
+	for _, key := range attributes.Keys() {
+		o = append(o, member{key, attributes[key]})
+	}
+	return o
+}
+
+// Encode writes a graph in the json0 schema of Graphviz. The objects array
+// holds the subgraphs, in pre-order, followed by the nodes, so the _gvid of a
+// node is its index plus _subgraph_cnt; edges have their own _gvid sequence
+// and refer to their endpoints by the _gvid of the nodes. Nodes and edges
+// carry their resolved attributes, ports are written as the tailport and
+// headport attributes and anonymous subgraphs are named "%<_gvid>".
+func Encode(graph parser.Graph) string {
+	return EncodeModel(model.Resolve(graph))
+}
+
+func EncodeModel(graph *model.Graph) string {
+	var subgraphs []*model.Subgraph
+	var collect func([]*model.Subgraph)
+	collect = func(children []*model.Subgraph) {
+		for _, child := range children {
+			subgraphs = append(subgraphs, child)
+			collect(child.Subgraphs)
+		}
+	}
+	collect(graph.Subgraphs)
+
+	gvids := make(map[*model.Subgraph]int, len(subgraphs))
+	for i, subgraph := range subgraphs {
+		gvids[subgraph] = i
+	}
+	nodeGvid := func(node *model.Node) int {
+		return len(subgraphs) + node.Index
+	}
+
+	var objects []object
+	for i, subgraph := range subgraphs {
+		sub := object{
+			{"_gvid", i},
+			{"name", subgraph.Name.OrElse(fmt.Sprintf("%%%d", i))},
+		}
+		sub = withAttributes(sub, subgraph.Attributes)
+
+		if len(subgraph.Subgraphs) > 0 {
+			children := make([]int, len(subgraph.Subgraphs))
+			for i, child := range subgraph.Subgraphs {
+				children[i] = gvids[child]
+			}
+			sub = append(sub, member{"subgraphs", children})
+		}
+		if nodes := subgraph.AllNodes(); len(nodes) > 0 {
+			members := make([]int, len(nodes))
+			for i, node := range nodes {
+				members[i] = nodeGvid(node)
+			}
+			sort.Ints(members)
+			sub = append(sub, member{"nodes", members})
+		}
+		if edges := allEdges(subgraph); len(edges) > 0 {
+			sub = append(sub, member{"edges", edges})
+		}
+		objects = append(objects, sub)
+	}
+	for _, node := range graph.Nodes {
+		objects = append(objects, withAttributes(object{{"_gvid", nodeGvid(node)}, {"name", node.Name}}, node.Attributes))
+	}
+
+	edges := make([]object, len(graph.Edges))
+	for i, edge := range graph.Edges {
+		edges[i] = withAttributes(object{
+			{"_gvid", edge.Index},
+			{"tail", nodeGvid(edge.Tail)},
+			{"head", nodeGvid(edge.Head)},
+		}, edge.AttributesWithPorts())
+	}
+
+	root := object{
+		{"name", graph.Name.OrElse("")},
+		{"directed", graph.IsDirect},
+		{"strict", graph.IsStrict},
+		{"_subgraph_cnt", len(subgraphs)},
+	}
+	root = withAttributes(root, graph.Attributes)
+	if len(objects) > 0 {
+		root = append(root, member{"objects", objects})
+	}
+	if len(edges) > 0 {
+		root = append(root, member{"edges", edges})
+	}
+
+	// Marshalling only fails on unsupported values, and object only holds
+	// strings, numbers, booleans and slices of them
+	data, _ := json.MarshalIndent(root, "", "  ")
+	return string(data) + "\n"
+}
+
+// allEdges returns the sorted indices of the edges declared in a subgraph or
+// in its nested subgraphs
+func allEdges(subgraph *model.Subgraph) []int {
+	seen := make(map[int]bool)
+	var visit func(*model.Subgraph)
+	visit = func(subgraph *model.Subgraph) {
+		for _, edge := range subgraph.Edges {
+			seen[edge.Index] = true
+		}
+		for _, child := range subgraph.Subgraphs {
+			visit(child)
+		}
+	}
+	visit(subgraph)
+
+	edges := make([]int, 0, len(seen))
+	for index := range seen {
+		edges = append(edges, index)
+	}
+	sort.Ints(edges)
+	return edges
+}
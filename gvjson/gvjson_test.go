@@ -0,0 +1,86 @@
+package gvjson
+
+import (
+	"dot-parser/builder"
+	"dot-parser/diff"
+	"dot-parser/internal/testutil"
+	"dot-parser/parser"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	expected, err := os.ReadFile("testdata/clusters.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if encoded := Encode(testutil.ReadGraph(t, "testdata/clusters.gv")); encoded != string(expected) {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, encoded)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	expected := testutil.ReadGraph(t, "testdata/clusters.gv")
+	decoded := testutil.DecodeFile(t, "testdata/clusters.json", Decode)
+
+	if !diff.Equal(expected, decoded) {
+		t.Fatalf("Expected decoded graph to be equal, got changes:\n%s", diff.Compare(expected, decoded))
+	}
+}
+
+func TestDecodeLayout(t *testing.T) {
+	expected := builder.Digraph().
+		Attr("bb", "0,0,62,116").
+		Attr("xdotversion", "1.7").
+		Node("a", parser.AttributeMap{"height": "0.5", "pos": "27,90", "width": "0.75"}).
+		Node("b", parser.AttributeMap{"height": "0.5", "pos": "27,18", "width": "0.75"}).
+		EdgeID(builder.Port("a", "s"), builder.ID("b"), parser.AttributeMap{"pos": "e,27,36.104 27,71.697 27,63.983 27,54.712 27,46.112"}).
+		Subgraph("cluster_x", func(sub *builder.Builder) {
+			sub.Attr("bb", "8,8,54,108").Attr("label", "").Node("a")
+		}).
+		Build()
+
+	if decoded := testutil.DecodeFile(t, "testdata/layout.json", Decode); !diff.Equal(expected, decoded) {
+		t.Fatalf("Expected decoded graph to be equal, got changes:\n%s", diff.Compare(expected, decoded))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	graph := builder.Graph().Strict().
+		EdgeDefaults(parser.AttributeMap{"color": "red"}).
+		Path([]string{"a", "b", "c"}).
+		Edge("c", "a").
+		AnonymousSubgraph(func(sub *builder.Builder) {
+			sub.Edge("c", "d", parser.AttributeMap{"weight": "2"})
+		}).
+		Build()
+
+	decoded := Decode(strings.NewReader(Encode(graph)))
+	if decoded.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", decoded.UnwrapErr())
+	}
+	if !diff.Equal(graph, decoded.Unwrap()) {
+		t.Fatalf("Expected decoded graph to be equal, got changes:\n%s", diff.Compare(graph, decoded.Unwrap()))
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	documents := []string{
+		`[]`,
+		`{"directed": "yes"}`,
+		`{"_subgraph_cnt": 2, "objects": [{"name": "a"}]}`,
+		`{"objects": [{"name": "a"}], "edges": [{"tail": 0, "head": 1}]}`,
+		`{"_subgraph_cnt": 1, "objects": [{"name": "s", "nodes": [3]}, {"name": "a"}]}`,
+		`{"_subgraph_cnt": 1, "objects": [{"name": "s", "subgraphs": [0]}]}`,
+		`{"_subgraph_cnt": 2, "objects": [{"_gvid": 0, "name": "s"}, {"_gvid": 0, "name": "t"}]}`,
+		`{"_subgraph_cnt": 2, "objects": [{"name": "s", "subgraphs": [1]}, {"name": "t", "subgraphs": [0]}]}`,
+	}
+
+	for _, document := range documents {
+		if res := Decode(strings.NewReader(document)); res.IsOk() {
+			t.Errorf("Expected error for %s", document)
+		}
+	}
+}
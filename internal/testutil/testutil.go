@@ -0,0 +1,66 @@
+// Package testutil holds the fixtures shared by the tests of the other
+// packages
+package testutil
+
+import (
+	"dot-parser/lexer"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// ParseGraph parses DOT source, failing the test on errors
+func ParseGraph(t *testing.T, input string) parser.Graph {
+	t.Helper()
+	return unwrap(t, parser.ParseFile(strings.NewReader(input)))
+}
+
+// ReadGraph parses a DOT file, failing the test on errors
+func ReadGraph(t *testing.T, path string) parser.Graph {
+	t.Helper()
+	return DecodeFile(t, path, parser.ParseFile)
+}
+
+// DecodeFile reads a graph from a file with decode, failing the test on
+// errors
+func DecodeFile(t *testing.T, path string, decode func(io.Reader) Result[parser.Graph]) parser.Graph {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	return unwrap(t, decode(file))
+}
+
+func unwrap(t *testing.T, res Result[parser.Graph]) parser.Graph {
+	t.Helper()
+	if res.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", res.UnwrapErr())
+	}
+	return res.Unwrap()
+}
+
+// ClearPositions resets the positions of the statements, so that parsed
+// graphs can be compared to built ones
+func ClearPositions(stmts []parser.Statement) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *parser.Node:
+			stmt.Position = lexer.Position{}
+		case *parser.Edge:
+			stmt.Position = lexer.Position{}
+		case *parser.AttributeStmt:
+			stmt.Position = lexer.Position{}
+		case *parser.SingleAttribute:
+			stmt.Position = lexer.Position{}
+		case *parser.Subgraph:
+			stmt.Position = lexer.Position{}
+			ClearPositions(stmt.Statements)
+		}
+	}
+}
@@ -31,13 +31,25 @@ type Lexer struct {
 	iter            iterator.PeekableIterator[rune]
 	startPosition   Position
 	currentPosition Position
+	keepComments    bool
 }
 
 func MakeLexer(reader io.Reader) iterator.Iterator[result.Result[TokenData]] {
+	return makeLexer(reader, false)
+}
+
+// MakeLexerWithComments returns a lexer that emits a COMMENT token, whose
+// lexeme is the full text of the comment, for every comment it reads
+func MakeLexerWithComments(reader io.Reader) iterator.Iterator[result.Result[TokenData]] {
+	return makeLexer(reader, true)
+}
+
+func makeLexer(reader io.Reader, keepComments bool) iterator.Iterator[result.Result[TokenData]] {
 	lexer := &Lexer{
 		iter:            nil,
 		startPosition:   Position{line: 1, column: 1},
 		currentPosition: Position{line: 1, column: 1},
+		keepComments:    keepComments,
 	}
 
 	iter := lexerIterator{
@@ -97,8 +109,15 @@ func (lexer *Lexer) next() result.Result[TokenData] {
 		case '/':
 			commentMatched := lexer.matchComment(char, lexer.iter)
 			if commentMatched.IsOk() {
-				lexer.iter = commentMatched.Unwrap()
-				lexer.iter.Next()
+				var text string
+				text, lexer.iter = commentMatched.Unwrap().Get()
+				closing := lexer.iter.Next()
+				if lexer.keepComments {
+					if closing.IsSome() && closing.Unwrap() == '/' {
+						text += "/"
+					}
+					return lexer.makeTokenData(COMMENT, Lexeme(text))
+				}
 				lexer.startPosition = lexer.currentPosition
 			} else {
 				err := commentMatched.UnwrapErr().Error()
@@ -2,45 +2,59 @@ package lexer
 
 import (
 	"dot-parser/iterator"
+	"dot-parser/pair"
 	"dot-parser/result"
 	"errors"
 )
 
-func (lexer *Lexer) matchComment(firstChar rune, iter iterator.PeekableIterator[rune]) result.Result[iterator.PeekableIterator[rune]] {
+type commentMatch = pair.Pair[string, iterator.PeekableIterator[rune]]
+
+// matchComment reads a comment up to, but excluding, its closing character
+// ('\n' for line comments, the final '/' for block comments)
+func (lexer *Lexer) matchComment(firstChar rune, iter iterator.PeekableIterator[rune]) result.Result[commentMatch] {
 	switch firstChar {
 	case '/':
 		next := result.FromOption(iter.Next(), errors.New("invalid comment"))
 
-		return result.FlatMap(next, func(char rune) result.Result[iterator.PeekableIterator[rune]] {
+		return result.FlatMap(next, func(char rune) result.Result[commentMatch] {
 			if char == '*' {
-				return result.Ok(lexer.skipMultiLineComment(iter))
+				text, iter := lexer.skipMultiLineComment(iter)
+				return result.Ok(pair.NewPair("/*"+text, iter))
 			} else if char == '/' {
-				return result.Ok(lexer.skipLine(iter))
+				text, iter := lexer.skipLine(iter)
+				return result.Ok(pair.NewPair("//"+text, iter))
 			} else {
-				return result.Err[iterator.PeekableIterator[rune]](errors.New("invalid comment"))
+				return result.Err[commentMatch](errors.New("invalid comment"))
 			}
 		})
 	case '#':
 		if lexer.startPosition.column == 1 {
-			return result.Ok(lexer.skipLine(iter))
+			text, iter := lexer.skipLine(iter)
+			return result.Ok(pair.NewPair("#"+text, iter))
 		}
 		fallthrough
 	default:
-		return result.Err[iterator.PeekableIterator[rune]](errors.New("invalid comment"))
+		return result.Err[commentMatch](errors.New("invalid comment"))
 	}
 }
 
-func (lexer *Lexer) skipLine(iter iterator.PeekableIterator[rune]) iterator.PeekableIterator[rune] {
-	return iterator.SkipWhile(iter, func(char rune) bool {
-		return char != '\n' && char != '\x03'
+func (lexer *Lexer) skipLine(iter iterator.PeekableIterator[rune]) (string, iterator.PeekableIterator[rune]) {
+	return iterator.FoldWhile("", iter, func(accum string, char rune) (bool, string) {
+		if char != '\n' && char != '\x03' {
+			return true, accum + string(char)
+		}
+		return false, accum
 	})
 }
 
-func (lexer *Lexer) skipMultiLineComment(iter iterator.PeekableIterator[rune]) iterator.PeekableIterator[rune] {
+func (lexer *Lexer) skipMultiLineComment(iter iterator.PeekableIterator[rune]) (string, iterator.PeekableIterator[rune]) {
 	var lastChar rune
-	return iterator.SkipWhile(iter, func(char rune) bool {
+	return iterator.FoldWhile("", iter, func(accum string, char rune) (bool, string) {
 		res := lastChar == '*' && char == '/'
 		lastChar = char
-		return !res && char != '\x03'
+		if !res && char != '\x03' {
+			return true, accum + string(char)
+		}
+		return false, accum
 	})
 }
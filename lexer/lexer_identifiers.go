@@ -18,16 +18,39 @@ func (lexer *Lexer) matchIdentifier(char rune, iter iterator.PeekableIterator[ru
 	}
 }
 
+// matchString reads a quoted string. As in Graphviz, the only escape
+// sequences are \" for a quote and a backslash before a newline, which joins
+// the two lines; "\\" is kept as it is and every other backslash is literal.
 func (lexer *Lexer) matchString(iter iterator.PeekableIterator[rune]) (result.Result[TokenData], iterator.PeekableIterator[rune]) {
+	escaped := false
 	lexeme, iter := iterator.FoldWhile("", iter, func(accum string, char rune) (bool, string) {
-		if char != '"' {
+		if escaped {
+			escaped = false
+			switch char {
+			case '"':
+				return true, accum + "\""
+			case '\n':
+				return true, accum
+			case '\x03':
+				return false, accum + "\\"
+			default:
+				return true, accum + "\\" + string(char)
+			}
+		} else if char == '\\' {
+			escaped = true
+			return true, accum
+		} else if char != '"' && char != '\x03' {
 			return true, accum + string(char)
 		} else {
 			return false, accum
 		}
 	})
 
-	return lexer.makeTokenData(ID, Lexeme(lexeme)), iter
+	if closing := iter.Next(); closing.IsNone() || closing.Unwrap() != '"' {
+		return lexer.makeTokenError("unterminated string"), iter
+	}
+
+	return lexer.makeQuotedTokenData(Lexeme(lexeme)), iter
 }
 
 func (lexer *Lexer) matchAlphaNumeric(char rune, iter iterator.PeekableIterator[rune]) (result.Result[TokenData], iterator.PeekableIterator[rune]) {
@@ -34,6 +34,9 @@ const (
 	EDGE
 	SUBGRAPH
 
+	// Only emitted by MakeLexerWithComments
+	COMMENT
+
 	EOF
 )
 
@@ -50,6 +53,7 @@ type TokenData struct {
 	position Position
 	token    Token
 	lexeme   Lexeme
+	quoted   bool
 }
 
 func (lexer *Lexer) makeTokenData(token Token, lexeme Lexeme) result.Result[TokenData] {
@@ -62,6 +66,17 @@ func (lexer *Lexer) makeTokenData(token Token, lexeme Lexeme) result.Result[Toke
 	)
 }
 
+func (lexer *Lexer) makeQuotedTokenData(lexeme Lexeme) result.Result[TokenData] {
+	return result.Ok(
+		TokenData{
+			position: lexer.startPosition,
+			token:    ID,
+			lexeme:   lexeme,
+			quoted:   true,
+		},
+	)
+}
+
 func (token TokenData) Position() Position {
 	return token.position
 }
@@ -72,6 +87,11 @@ func (token TokenData) Lexeme() Lexeme {
 	return token.lexeme
 }
 
+// IsQuoted reports whether an ID token was written as a quoted string
+func (token TokenData) IsQuoted() bool {
+	return token.quoted
+}
+
 type TokenError struct {
 	position Position
 	message  string
@@ -130,6 +150,8 @@ func (token Token) String() string {
 		return "'edge'"
 	case SUBGRAPH:
 		return "'subgraph'"
+	case COMMENT:
+		return "comment"
 	case EOF:
 		return "EOF"
 	default:
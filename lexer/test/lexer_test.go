@@ -147,6 +147,28 @@ func TestStringIdentifier(t *testing.T) {
 	testIdToken(t, "\"my string\"", "my string", "Expected String")
 }
 
+func TestStringIdentifierFollowedByToken(t *testing.T) {
+	var lex = getLexer("\"my string\" ]")
+
+	lex.Next()
+	res := lex.Next().Unwrap().Unwrap()
+	if res.Token() != lexer.CLOSE_SQUARE_BRACKET {
+		printToken(t, "Expected Closed Square Bracket after String", res.Position(), res.Token(), res.Lexeme())
+	}
+}
+
+func TestStringIdentifierEscapes(t *testing.T) {
+	testIdToken(t, "\"say \\\"hi\\\" \\\\ \\l\\\nthere\"", "say \"hi\" \\\\ \\lthere", "Expected String with escapes")
+}
+
+func TestUnterminatedStringIdentifier(t *testing.T) {
+	var lex = getLexer("\"my string")
+
+	if res := lex.Next().Unwrap(); res.IsOk() {
+		t.Errorf("Expected error for unterminated String, got %v", res.Unwrap())
+	}
+}
+
 func TestAlphaNumericIdentifier(t *testing.T) {
 	testIdToken(t, "identifier123", "identifier123", "Expected Identifier")
 }
@@ -226,3 +248,29 @@ func TestTokenizeExample(t *testing.T) {
 		i += 1
 	}
 }
+
+func TestComments(t *testing.T) {
+	var lex = lexer.MakeLexerWithComments(strings.NewReader("# line\na /* block\n */ b // end"))
+
+	var expectedTokens = []lexer.Token{lexer.COMMENT, lexer.ID, lexer.COMMENT, lexer.ID, lexer.COMMENT, lexer.EOF}
+	var expectedLexemes = []string{"# line", "a", "/* block\n */", "b", "// end", ""}
+
+	for i := range expectedTokens {
+		res := lex.Next().Unwrap().Unwrap()
+		if res.Token() != expectedTokens[i] || res.Lexeme() != lexer.Lexeme(expectedLexemes[i]) {
+			printToken(t, "Expected", res.Position(), expectedTokens[i], lexer.Lexeme(expectedLexemes[i]))
+			printToken(t, "Got", res.Position(), res.Token(), res.Lexeme())
+		}
+	}
+}
+
+func TestQuotedID(t *testing.T) {
+	var lex = getLexer(`"a" a`)
+
+	if !lex.Next().Unwrap().Unwrap().IsQuoted() {
+		t.Errorf("Expected quoted string to be quoted")
+	}
+	if lex.Next().Unwrap().Unwrap().IsQuoted() {
+		t.Errorf("Expected identifier not to be quoted")
+	}
+}
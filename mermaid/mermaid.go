@@ -0,0 +1,406 @@
+package mermaid
+
+import (
+	"dot-parser/alias"
+	"dot-parser/attribute"
+	. "dot-parser/lexer"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"fmt"
+	"strings"
+)
+
+// Unsupported is an attribute that has no Mermaid equivalent and was left
+// out of the export
+type Unsupported struct {
+	// Element is "graph", "subgraph <name>", "node <name>" or
+	// "edge <tail> -> <head>"
+	Element  string
+	Position Position
+	Key      string
+	Value    string
+}
+
+func (unsupported Unsupported) String() string {
+	return fmt.Sprintf("%s: unsupported attribute %s=%q", unsupported.Element, unsupported.Key, unsupported.Value)
+}
+
+var keywords = []string{
+	"end", "graph", "flowchart", "subgraph", "direction", "style", "classDef",
+	"class", "click", "linkStyle", "default", "call", "href",
+}
+
+// shape holds the delimiters of a Mermaid node shape
+type shape [2]string
+
+// shapes maps Graphviz node shapes to Mermaid node shapes
+var shapes = map[string]shape{
+	"box":           {"[", "]"},
+	"rect":          {"[", "]"},
+	"rectangle":     {"[", "]"},
+	"square":        {"[", "]"},
+	"ellipse":       {"([", "])"},
+	"oval":          {"([", "])"},
+	"circle":        {"((", "))"},
+	"point":         {"((", "))"},
+	"doublecircle":  {"(((", ")))"},
+	"diamond":       {"{", "}"},
+	"hexagon":       {"{{", "}}"},
+	"parallelogram": {"[/", "/]"},
+	"trapezium":     {"[/", "\\]"},
+	"invtrapezium":  {"[\\", "/]"},
+	"cylinder":      {"[(", ")]"},
+}
+
+var directions = map[string]string{"TB": "TB", "LR": "LR", "BT": "BT", "RL": "RL"}
+
+type exporter struct {
+	graph       *model.Graph
+	aliases     *alias.Aliases
+	builder     strings.Builder
+	styles      []string
+	linkStyles  []string
+	unsupported []Unsupported
+}
+
+// Export writes a graph as a Mermaid flowchart and returns the attributes it
+// could not represent.
+//
+// The graph label becomes the title and rankdir the direction. Nodes are
+// written with their label and the Mermaid shape closest to their shape, a
+// stadium for ellipses, the default shape. Edges are written with an arrow
+// for directed graphs, or according to dir, and with their label; dashed
+// and dotted edges become dotted links, bold edges thick links and
+// invisible edges invisible links. Colors, pen widths and the
+// dashed, dotted, bold and filled styles become style and linkStyle
+// statements. Named subgraphs become Mermaid subgraphs, anonymous subgraphs
+// are flattened. IDs that are not Mermaid identifiers are replaced by
+// aliases, labelled with the original ID.
+func Export(graph parser.Graph) (string, []Unsupported) {
+	return ExportModel(model.Resolve(graph))
+}
+
+func ExportModel(graph *model.Graph) (string, []Unsupported) {
+	e := exporter{graph: graph, aliases: alias.New(keywords...)}
+	for _, node := range graph.Nodes {
+		e.aliases.Get(node.Name)
+	}
+
+	e.header()
+	placement := graph.Placement(isNamed)
+	e.nodes(placement, nil, "    ")
+	e.subgraphs(graph.Subgraphs, placement, "    ")
+	for _, edge := range graph.Edges {
+		e.edge(edge)
+	}
+
+	for _, style := range e.styles {
+		e.builder.WriteString("    " + style + "\n")
+	}
+	for _, style := range e.linkStyles {
+		e.builder.WriteString("    " + style + "\n")
+	}
+	return e.builder.String(), e.unsupported
+}
+
+func isNamed(subgraph *model.Subgraph) bool {
+	return subgraph.Name.IsSome()
+}
+
+func (e *exporter) report(element string, position Position, key string, value string) {
+	e.unsupported = append(e.unsupported, Unsupported{Element: element, Position: position, Key: key, Value: value})
+}
+
+// text writes a label as a quoted Mermaid string, with a <br> for each line
+// break
+func text(label string) string {
+	lines := attribute.SplitLines(label)
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "\"", "#quot;")
+	}
+	return "\"" + strings.Join(lines, "<br>") + "\""
+}
+
+func (e *exporter) header() {
+	graphName := e.graph.Name.OrElse("")
+	direction := "TB"
+	for _, key := range e.graph.Attributes.Keys() {
+		value := e.graph.Attributes[key]
+		switch key {
+		case "label":
+			label := attribute.ExpandEscString(value, attribute.EscContext{Graph: graphName})
+			e.builder.WriteString(fmt.Sprintf("---\ntitle: %q\n---\n", strings.Join(attribute.SplitLines(label), " ")))
+		case "rankdir":
+			if mapped, exists := directions[value]; exists {
+				direction = mapped
+			} else {
+				e.report("graph", Position{}, key, value)
+			}
+		default:
+			e.report("graph", Position{}, key, value)
+		}
+	}
+	e.builder.WriteString("flowchart " + direction + "\n")
+}
+
+// nodes writes the nodes placed in subgraph, or in no subgraph when nil
+func (e *exporter) nodes(placement map[*model.Node]*model.Subgraph, subgraph *model.Subgraph, indent string) {
+	for _, node := range e.graph.Nodes {
+		if placement[node] == subgraph {
+			e.builder.WriteString(indent + e.node(node) + "\n")
+		}
+	}
+}
+
+func (e *exporter) node(node *model.Node) string {
+	element := "node " + node.Name
+	id := e.aliases.Get(node.Name)
+
+	label := node.Name
+	// Graphviz draws nodes without a shape as ellipses
+	nodeShape := shapes["ellipse"]
+	var style style
+	for _, key := range node.Attributes.Keys() {
+		value := node.Attributes[key]
+		switch key {
+		case "label":
+			label = attribute.ExpandEscString(value, attribute.EscContext{Graph: e.graph.Name.OrElse(""), Node: node.Name})
+		case "shape":
+			if mapped, exists := shapes[value]; exists {
+				nodeShape = mapped
+			} else {
+				e.report(element, node.Position, key, value)
+			}
+		default:
+			if !style.add(key, value) {
+				e.report(element, node.Position, key, value)
+			}
+		}
+	}
+
+	for _, unsupported := range style.unsupported {
+		e.report(element, node.Position, "style", unsupported)
+	}
+	if style.rounded && nodeShape == shapes["box"] {
+		nodeShape = shape{"(", ")"}
+	}
+	if css := style.css(); css != "" {
+		e.styles = append(e.styles, "style "+id+" "+css)
+	}
+
+	return id + nodeShape[0] + text(label) + nodeShape[1]
+}
+
+func (e *exporter) subgraphs(subgraphs []*model.Subgraph, placement map[*model.Node]*model.Subgraph, indent string) {
+	for _, subgraph := range subgraphs {
+		if !isNamed(subgraph) {
+			element := "subgraph"
+			for _, key := range subgraph.Attributes.Keys() {
+				e.report(element, Position{}, key, subgraph.Attributes[key])
+			}
+			e.subgraphs(subgraph.Subgraphs, placement, indent)
+			continue
+		}
+
+		name := subgraph.Name.Unwrap()
+		element := "subgraph " + name
+		id := e.aliases.Unique(name)
+
+		// Graphviz shows no title for subgraphs without a label
+		label := " "
+		direction := ""
+		var style style
+		for _, key := range subgraph.Attributes.Keys() {
+			value := subgraph.Attributes[key]
+			switch key {
+			case "label":
+				label = attribute.ExpandEscString(value, attribute.EscContext{Graph: name})
+			case "rankdir":
+				if mapped, exists := directions[value]; exists {
+					direction = mapped
+				} else {
+					e.report(element, Position{}, key, value)
+				}
+			case "bgcolor":
+				if !style.color("fill", value) {
+					e.report(element, Position{}, key, value)
+				}
+			case "pencolor":
+				if !style.color("stroke", value) {
+					e.report(element, Position{}, key, value)
+				}
+			default:
+				if !style.add(key, value) {
+					e.report(element, Position{}, key, value)
+				}
+			}
+		}
+		for _, unsupported := range style.unsupported {
+			e.report(element, Position{}, "style", unsupported)
+		}
+		if css := style.css(); css != "" {
+			e.styles = append(e.styles, "style "+id+" "+css)
+		}
+
+		e.builder.WriteString(indent + "subgraph " + id + " [" + text(label) + "]\n")
+		if direction != "" {
+			e.builder.WriteString(indent + "    direction " + direction + "\n")
+		}
+		e.nodes(placement, subgraph, indent+"    ")
+		e.subgraphs(subgraph.Subgraphs, placement, indent+"    ")
+		e.builder.WriteString(indent + "end\n")
+	}
+}
+
+// link holds the parts of a Mermaid link: the line is "-", "." (dotted) or
+// "=" (thick) and the ends are "" (none), ">" (arrow) or "o" (circle)
+type link struct {
+	line      string
+	tailEnd   string
+	headEnd   string
+	invisible bool
+}
+
+func (l link) String() string {
+	if l.invisible {
+		return "~~~"
+	}
+
+	tail := l.tailEnd
+	if tail == ">" {
+		tail = "<"
+	}
+
+	var body string
+	switch {
+	case l.line == ".":
+		body = "-.-"
+	case l.line == "=" && l.headEnd == "":
+		body = "==="
+	case l.line == "=":
+		body = "=="
+	case l.headEnd == "":
+		body = "---"
+	default:
+		body = "--"
+	}
+	return tail + body + l.headEnd
+}
+
+func (e *exporter) edge(edge *model.Edge) {
+	arc := "--"
+	if e.graph.IsDirect {
+		arc = "->"
+	}
+	element := "edge " + edge.Tail.Name + " " + arc + " " + edge.Head.Name
+
+	tail, head := edge.Tail, edge.Head
+	l := link{line: "-"}
+	dir := "none"
+	if e.graph.IsDirect {
+		dir = "forward"
+	}
+	arrowhead, arrowtail := ">", ">"
+	arrowtailValue := "normal"
+
+	label := ""
+	var style style
+	for _, key := range edge.Attributes.Keys() {
+		value := edge.Attributes[key]
+		switch key {
+		case "label":
+			label = attribute.ExpandEscString(value, attribute.EscContext{
+				Graph:    e.graph.Name.OrElse(""),
+				Tail:     edge.Tail.Name,
+				Head:     edge.Head.Name,
+				IsDirect: e.graph.IsDirect,
+			})
+		case "dir":
+			if value == "forward" || value == "back" || value == "both" || value == "none" {
+				dir = value
+			} else {
+				e.report(element, edge.Position, key, value)
+			}
+		case "arrowhead", "arrowtail":
+			head, supported := arrowHead(value)
+			if !supported {
+				e.report(element, edge.Position, key, value)
+			} else if key == "arrowhead" {
+				arrowhead = head
+			} else {
+				arrowtail, arrowtailValue = head, value
+			}
+		case "style":
+			items := attribute.ParseStyle(value)
+			if items.IsErr() {
+				e.report(element, edge.Position, key, value)
+				continue
+			}
+			for _, item := range items.Unwrap() {
+				switch item.Name {
+				case "solid":
+				case "dashed", "dotted":
+					l.line = "."
+				case "bold":
+					l.line = "="
+				case "invis":
+					l.invisible = true
+				default:
+					e.report(element, edge.Position, key, item.Name)
+				}
+			}
+		default:
+			if !style.addLink(key, value) {
+				e.report(element, edge.Position, key, value)
+			}
+		}
+	}
+	if edge.TailPort.IsSome() {
+		e.report(element, edge.Position, "tailport", edge.TailPort.Unwrap())
+	}
+	if edge.HeadPort.IsSome() {
+		e.report(element, edge.Position, "headport", edge.HeadPort.Unwrap())
+	}
+
+	switch dir {
+	case "forward":
+		l.headEnd = arrowhead
+	case "back":
+		tail, head = head, tail
+		l.headEnd = arrowtail
+	case "both":
+		l.tailEnd, l.headEnd = arrowtail, arrowhead
+		if l.headEnd == "" {
+			tail, head = head, tail
+			l.tailEnd, l.headEnd = l.headEnd, l.tailEnd
+		} else if l.tailEnd != "" && l.tailEnd != l.headEnd {
+			// Mermaid links have the same shape at both ends
+			e.report(element, edge.Position, "arrowtail", arrowtailValue)
+			l.tailEnd = ""
+		}
+	}
+
+	line := e.aliases.Get(tail.Name) + " " + l.String()
+	if label != "" && !l.invisible {
+		line += "|" + text(label) + "|"
+	}
+	e.builder.WriteString("    " + line + " " + e.aliases.Get(head.Name) + "\n")
+
+	if css := style.css(); css != "" {
+		e.linkStyles = append(e.linkStyles, fmt.Sprintf("linkStyle %d %s", edge.Index, css))
+	}
+}
+
+// arrowHead maps the arrow shapes that have a Mermaid equivalent
+func arrowHead(value string) (string, bool) {
+	switch value {
+	case "normal", "vee", "open", "empty", "onormal":
+		return ">", true
+	case "none":
+		return "", true
+	case "dot", "odot":
+		return "o", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,105 @@
+package mermaid
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		rankdir=LR
+		label="Flow"
+		node [shape=box]
+		start [shape=circle, label="Start\nhere"]
+		"end" [shape=diamond, style=filled, fillcolor="#ff0000"]
+		subgraph cluster_0 {
+			label="Phase \"1\""
+			x -> y [style=dashed, label="go"]
+		}
+		{ z }
+		start -> x [color=blue, penwidth=2]
+		y -> "end" [style=bold]
+		z -> "end" [dir=both]
+	}`)
+
+	expected := `---
+title: "Flow"
+---
+flowchart LR
+    start(("Start<br>here"))
+    end_1{"end"}
+    z["z"]
+    subgraph cluster_0 ["Phase #quot;1#quot;"]
+        x["x"]
+        y["y"]
+    end
+    x -.->|"go"| y
+    start --> x
+    y ==> end_1
+    z <--> end_1
+    style end_1 fill:#ff0000
+    linkStyle 1 stroke:#0000ff,stroke-width:2px
+`
+	exported, unsupported := Export(graph)
+	if exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+	if len(unsupported) != 0 {
+		t.Fatalf("Expected no unsupported attributes, got %v", unsupported)
+	}
+}
+
+func TestExportUndirected(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { a -- b; b -- c [dir=forward, arrowhead=dot]; c -- a [style=invis] }`)
+
+	expected := `flowchart TB
+    a(["a"])
+    b(["b"])
+    c(["c"])
+    a --- b
+    b --o c
+    c ~~~ a
+`
+	if exported, _ := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportDefaultShape(t *testing.T) {
+	implicit, _ := Export(testutil.ParseGraph(t, `digraph { a }`))
+	explicit, _ := Export(testutil.ParseGraph(t, `digraph { a [shape=ellipse] }`))
+	if implicit != explicit {
+		t.Fatalf("Expected nodes without a shape to be ellipses:\n%s\ngot:\n%s", explicit, implicit)
+	}
+}
+
+func TestExportUnsupported(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		splines=ortho
+		a [shape=cds, style="filled,striped"]
+		a:n -> b [arrowhead=tee]
+		{ rank=same; b }
+	}`)
+
+	expected := []string{
+		`graph: unsupported attribute splines="ortho"`,
+		`node a: unsupported attribute shape="cds"`,
+		`node a: unsupported attribute style="striped"`,
+		`subgraph: unsupported attribute rank="same"`,
+		`edge a -> b: unsupported attribute arrowhead="tee"`,
+		`edge a -> b: unsupported attribute tailport="n"`,
+	}
+
+	_, unsupported := Export(graph)
+	if len(unsupported) != len(expected) {
+		t.Fatalf("Expected %d unsupported attributes, got %v", len(expected), unsupported)
+	}
+	for i, attribute := range unsupported {
+		if attribute.String() != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], attribute)
+		}
+	}
+	if unsupported[1].Position.Line() != 3 {
+		t.Errorf("Expected the position of node a, got line %d", unsupported[1].Position.Line())
+	}
+}
@@ -0,0 +1,117 @@
+package mermaid
+
+import (
+	"dot-parser/attribute"
+	"strings"
+)
+
+// style collects the CSS properties of a node, subgraph or link
+type style struct {
+	properties  map[string]string
+	filled      bool
+	rounded     bool
+	fillcolor   string
+	unsupported []string
+}
+
+// cssOrder is the order in which properties are written
+var cssOrder = []string{"fill", "stroke", "stroke-width", "stroke-dasharray", "color"}
+
+func (s *style) set(property string, value string) {
+	if s.properties == nil {
+		s.properties = make(map[string]string)
+	}
+	s.properties[property] = value
+}
+
+// color sets property to a Graphviz color, reporting whether it is one
+func (s *style) color(property string, value string) bool {
+	color := attribute.ParseColor(value)
+	if color.IsErr() {
+		return false
+	}
+	s.set(property, attribute.FormatColor(color.Unwrap()))
+	return true
+}
+
+// add reads a node or subgraph attribute, reporting whether it is supported
+func (s *style) add(key string, value string) bool {
+	switch key {
+	case "fillcolor":
+		color := attribute.ParseColor(value)
+		if color.IsOk() {
+			s.fillcolor = attribute.FormatColor(color.Unwrap())
+		}
+		return color.IsOk()
+	case "style":
+		items := attribute.ParseStyle(value)
+		if items.IsErr() {
+			return false
+		}
+		for _, item := range items.Unwrap() {
+			switch item.Name {
+			case "solid":
+			case "filled":
+				s.filled = true
+			case "rounded":
+				s.rounded = true
+			case "dashed":
+				s.set("stroke-dasharray", "5 5")
+			case "dotted":
+				s.set("stroke-dasharray", "2 2")
+			case "bold":
+				if _, exists := s.properties["stroke-width"]; !exists {
+					s.set("stroke-width", "2px")
+				}
+			default:
+				s.unsupported = append(s.unsupported, item.Name)
+			}
+		}
+		return true
+	default:
+		return s.addLink(key, value)
+	}
+}
+
+// addLink reads an attribute shared by nodes, subgraphs and edges, reporting
+// whether it is supported
+func (s *style) addLink(key string, value string) bool {
+	switch key {
+	case "color":
+		return s.color("stroke", value)
+	case "fontcolor":
+		return s.color("color", value)
+	case "penwidth":
+		width := attribute.ParseDouble(value)
+		if width.IsOk() {
+			s.set("stroke-width", attribute.FormatDouble(width.Unwrap())+"px")
+		}
+		return width.IsOk()
+	default:
+		return false
+	}
+}
+
+// css writes the properties as "name:value,..." in a fixed order
+func (s *style) css() string {
+	if s.filled {
+		fill := s.fillcolor
+		if fill == "" {
+			fill = s.properties["stroke"]
+		}
+		if fill == "" {
+			fill = "#d3d3d3"
+		}
+		if _, exists := s.properties["fill"]; !exists {
+			s.set("fill", fill)
+		}
+	}
+
+	var properties []string
+	for _, property := range cssOrder {
+		if value, exists := s.properties[property]; exists {
+			properties = append(properties, property+":"+value)
+		}
+	}
+	return strings.Join(properties, ",")
+}
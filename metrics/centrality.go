@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"dot-parser/model"
+	"math"
+)
+
+// adjacency lists the distinct successors of every node by index, other
+// than itself; undirected edges lead both ways
+func adjacency(graph *model.Graph) [][]int {
+	successors := make([][]int, len(graph.Nodes))
+	seen := make(map[[2]int]bool)
+	add := func(tail int, head int) {
+		if tail != head && !seen[[2]int{tail, head}] {
+			seen[[2]int{tail, head}] = true
+			successors[tail] = append(successors[tail], head)
+		}
+	}
+	for _, edge := range graph.Edges {
+		add(edge.Tail.Index, edge.Head.Index)
+		if !graph.IsDirect {
+			add(edge.Head.Index, edge.Tail.Index)
+		}
+	}
+	return successors
+}
+
+// distances returns the number of edges of the shortest paths from a node
+// to every other one, -1 for the ones that cannot be reached, along with
+// the nodes in the order they were reached
+func distances(successors [][]int, source int) ([]int, []int) {
+	distance := make([]int, len(successors))
+	for i := range distance {
+		distance[i] = -1
+	}
+	distance[source] = 0
+	queue := []int{source}
+	for next := 0; next < len(queue); next++ {
+		node := queue[next]
+		for _, successor := range successors[node] {
+			if distance[successor] == -1 {
+				distance[successor] = distance[node] + 1
+				queue = append(queue, successor)
+			}
+		}
+	}
+	return distance, queue
+}
+
+// clustering computes the local clustering coefficient of every node: the
+// fraction of the pairs of its neighbours that are neighbours themselves,
+// ignoring edge directions, loops and parallel edges. Nodes with less than
+// two neighbours have a coefficient of 0.
+func clustering(graph *model.Graph) []float64 {
+	neighbours := make([]map[int]bool, len(graph.Nodes))
+	for i := range neighbours {
+		neighbours[i] = make(map[int]bool)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Tail != edge.Head {
+			neighbours[edge.Tail.Index][edge.Head.Index] = true
+			neighbours[edge.Head.Index][edge.Tail.Index] = true
+		}
+	}
+
+	coefficients := make([]float64, len(graph.Nodes))
+	for i, adjacent := range neighbours {
+		if len(adjacent) < 2 {
+			continue
+		}
+		links := 0
+		for first := range adjacent {
+			for second := range adjacent {
+				if first < second && neighbours[first][second] {
+					links++
+				}
+			}
+		}
+		coefficients[i] = float64(links) / float64(len(adjacent)*(len(adjacent)-1)/2)
+	}
+	return coefficients
+}
+
+// betweenness computes the betweenness centrality of every node with the
+// algorithm of Brandes: the sum, over the pairs of other nodes, of the
+// fraction of the shortest paths between them going through the node.
+// Pairs of undirected graphs are counted once.
+func betweenness(graph *model.Graph, successors [][]int) []float64 {
+	centrality := make([]float64, len(graph.Nodes))
+	for source := range graph.Nodes {
+		distance, order := distances(successors, source)
+		paths := make([]float64, len(graph.Nodes))
+		paths[source] = 1
+		for _, node := range order {
+			for _, successor := range successors[node] {
+				if distance[successor] == distance[node]+1 {
+					paths[successor] += paths[node]
+				}
+			}
+		}
+
+		dependency := make([]float64, len(graph.Nodes))
+		for i := len(order) - 1; i >= 0; i-- {
+			node := order[i]
+			for _, successor := range successors[node] {
+				if distance[successor] == distance[node]+1 {
+					dependency[node] += paths[node] / paths[successor] * (1 + dependency[successor])
+				}
+			}
+			if node != source {
+				centrality[node] += dependency[node]
+			}
+		}
+	}
+
+	if !graph.IsDirect {
+		for i := range centrality {
+			centrality[i] /= 2
+		}
+	}
+	return centrality
+}
+
+// closeness computes the closeness centrality of every node: the inverse
+// of the mean distance to the nodes it reaches, scaled by the fraction of
+// the other nodes it reaches, so that nodes of small components are not
+// favoured. Nodes that reach no other node have a closeness of 0.
+func closeness(graph *model.Graph, successors [][]int) []float64 {
+	centrality := make([]float64, len(graph.Nodes))
+	for source := range graph.Nodes {
+		distance, order := distances(successors, source)
+		total := 0
+		for _, node := range order {
+			total += distance[node]
+		}
+		if reached := len(order) - 1; total > 0 {
+			centrality[source] = float64(reached) / float64(total) * float64(reached) / float64(len(graph.Nodes)-1)
+		}
+	}
+	return centrality
+}
+
+// pageRank computes the PageRank of every node by power iteration: each
+// node shares its rank among the edges leaving it, parallel edges counting
+// once each, and nodes without edges leaving them among all the nodes.
+// Undirected edges lead both ways.
+func pageRank(graph *model.Graph, options Options) []float64 {
+	count := float64(len(graph.Nodes))
+	rank := make([]float64, len(graph.Nodes))
+	for i := range rank {
+		rank[i] = 1 / count
+	}
+
+	outDegree := make([]int, len(graph.Nodes))
+	for _, edge := range graph.Edges {
+		outDegree[edge.Tail.Index]++
+		if !graph.IsDirect {
+			outDegree[edge.Head.Index]++
+		}
+	}
+
+	for iteration := 0; iteration < options.MaxIterations; iteration++ {
+		dangling := 0.0
+		for i, degree := range outDegree {
+			if degree == 0 {
+				dangling += rank[i]
+			}
+		}
+
+		next := make([]float64, len(graph.Nodes))
+		for i := range next {
+			next[i] = (1-options.Damping)/count + options.Damping*dangling/count
+		}
+		for _, edge := range graph.Edges {
+			tail, head := edge.Tail.Index, edge.Head.Index
+			next[head] += options.Damping * rank[tail] / float64(outDegree[tail])
+			if !graph.IsDirect {
+				next[tail] += options.Damping * rank[head] / float64(outDegree[head])
+			}
+		}
+
+		change := 0.0
+		for i := range rank {
+			change += math.Abs(next[i] - rank[i])
+		}
+		rank = next
+		if change < options.Tolerance {
+			break
+		}
+	}
+	return rank
+}
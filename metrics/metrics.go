@@ -0,0 +1,271 @@
+package metrics
+
+import (
+	"dot-parser/attribute"
+	"dot-parser/builder"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"sort"
+)
+
+type Options struct {
+	// Damping is the probability that PageRank follows an edge rather than
+	// jumping to any node
+	Damping float64
+	// Tolerance stops the PageRank iterations once the ranks change by
+	// less than it in total
+	Tolerance     float64
+	MaxIterations int
+}
+
+func DefaultOptions() Options {
+	return Options{Damping: 0.85, Tolerance: 1e-10, MaxIterations: 100}
+}
+
+// Metric names one of the metrics of a node, its String is the attribute
+// Annotate writes it to
+type Metric uint8
+
+const (
+	DEGREE Metric = iota
+	IN_DEGREE
+	OUT_DEGREE
+	CLUSTERING
+	BETWEENNESS
+	CLOSENESS
+	PAGERANK
+)
+
+var metricNames = []string{"degree", "indegree", "outdegree", "clustering", "betweenness", "closeness", "pagerank"}
+
+func (metric Metric) String() string {
+	return metricNames[metric]
+}
+
+// NodeMetrics holds the metrics of a node. Edges are followed in their
+// direction in digraphs and both ways in undirected graphs.
+type NodeMetrics struct {
+	Node *model.Node
+	// Degree counts the edges of the node, loops twice
+	Degree int
+	// InDegree and OutDegree count the edges entering and leaving the
+	// node, as they are written in undirected graphs
+	InDegree  int
+	OutDegree int
+	// Clustering is the local clustering coefficient
+	Clustering  float64
+	Betweenness float64
+	Closeness   float64
+	PageRank    float64
+}
+
+// Value returns one of the metrics of the node
+func (metrics NodeMetrics) Value(metric Metric) float64 {
+	switch metric {
+	case DEGREE:
+		return float64(metrics.Degree)
+	case IN_DEGREE:
+		return float64(metrics.InDegree)
+	case OUT_DEGREE:
+		return float64(metrics.OutDegree)
+	case CLUSTERING:
+		return metrics.Clustering
+	case BETWEENNESS:
+		return metrics.Betweenness
+	case CLOSENESS:
+		return metrics.Closeness
+	default:
+		return metrics.PageRank
+	}
+}
+
+// DegreeDistribution summarizes the degrees of the nodes of a graph
+type DegreeDistribution struct {
+	Min  int
+	Max  int
+	Mean float64
+	// Counts holds the number of nodes of each degree
+	Counts map[int]int
+}
+
+func distribution(degrees []int) DegreeDistribution {
+	result := DegreeDistribution{Counts: make(map[int]int)}
+	total := 0
+	for i, degree := range degrees {
+		if i == 0 || degree < result.Min {
+			result.Min = degree
+		}
+		if degree > result.Max {
+			result.Max = degree
+		}
+		total += degree
+		result.Counts[degree]++
+	}
+	if len(degrees) > 0 {
+		result.Mean = float64(total) / float64(len(degrees))
+	}
+	return result
+}
+
+// Report holds the metrics of a graph and of its nodes
+type Report struct {
+	NodeCount int
+	EdgeCount int
+	// Density is the ratio of the edges to the edges a graph without loops
+	// and parallel edges can have
+	Density float64
+	// Components counts the weakly connected components
+	Components int
+	Degrees    DegreeDistribution
+	// InDegrees and OutDegrees are only set for digraphs
+	InDegrees  DegreeDistribution
+	OutDegrees DegreeDistribution
+	// Diameter is the greatest number of edges of a shortest path, and
+	// AveragePathLength their mean, over the pairs of nodes joined by a path
+	Diameter          int
+	AveragePathLength float64
+	// Clustering is the mean of the local clustering coefficients
+	Clustering float64
+	// Nodes holds the metrics of every node, in declaration order
+	Nodes []NodeMetrics
+}
+
+// Compute computes the metrics of a graph and of its nodes, as gc and
+// dotstat report them along with centralities. Paths are counted in edges,
+// whatever their attributes.
+func Compute(graph parser.Graph, options Options) Report {
+	return ComputeModel(model.Resolve(graph), options)
+}
+
+func ComputeModel(graph *model.Graph, options Options) Report {
+	report := Report{NodeCount: len(graph.Nodes), EdgeCount: len(graph.Edges)}
+	if pairs := float64(len(graph.Nodes)) * float64(len(graph.Nodes)-1); pairs > 0 {
+		report.Density = float64(len(graph.Edges)) / pairs
+		if !graph.IsDirect {
+			report.Density *= 2
+		}
+	}
+
+	successors := adjacency(graph)
+	clustering := clustering(graph)
+	betweenness := betweenness(graph, successors)
+	closeness := closeness(graph, successors)
+	pageRank := pageRank(graph, options)
+
+	degrees := make([]int, len(graph.Nodes))
+	inDegrees := make([]int, len(graph.Nodes))
+	outDegrees := make([]int, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		inDegrees[i], outDegrees[i] = len(graph.In(node)), len(graph.Out(node))
+		degrees[i] = inDegrees[i] + outDegrees[i]
+		report.Nodes = append(report.Nodes, NodeMetrics{
+			Node:        node,
+			Degree:      degrees[i],
+			InDegree:    inDegrees[i],
+			OutDegree:   outDegrees[i],
+			Clustering:  clustering[i],
+			Betweenness: betweenness[i],
+			Closeness:   closeness[i],
+			PageRank:    pageRank[i],
+		})
+		report.Clustering += clustering[i] / float64(len(graph.Nodes))
+	}
+	report.Degrees = distribution(degrees)
+	if graph.IsDirect {
+		report.InDegrees, report.OutDegrees = distribution(inDegrees), distribution(outDegrees)
+	}
+
+	pathCount, pathLength := 0, 0
+	for source := range graph.Nodes {
+		distance, order := distances(successors, source)
+		for _, node := range order[1:] {
+			pathCount++
+			pathLength += distance[node]
+			if distance[node] > report.Diameter {
+				report.Diameter = distance[node]
+			}
+		}
+	}
+	if pathCount > 0 {
+		report.AveragePathLength = float64(pathLength) / float64(pathCount)
+	}
+
+	report.Components = countComponents(graph)
+	return report
+}
+
+// countComponents counts the weakly connected components
+func countComponents(graph *model.Graph) int {
+	parent := make([]int, len(graph.Nodes))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(node int) int {
+		for parent[node] != node {
+			parent[node] = parent[parent[node]]
+			node = parent[node]
+		}
+		return node
+	}
+
+	components := len(graph.Nodes)
+	for _, edge := range graph.Edges {
+		if tail, head := find(edge.Tail.Index), find(edge.Head.Index); tail != head {
+			parent[tail] = head
+			components--
+		}
+	}
+	return components
+}
+
+// Annotate returns graph with the metrics of every node as attributes
+// named after the metrics, in node statements at the end of the graph
+func Annotate(graph parser.Graph, report Report) parser.Graph {
+	annotated := graph
+	annotated.Statements = append([]parser.Statement(nil), graph.Statements...)
+	for _, node := range report.Nodes {
+		attributes := parser.AttributeMap{}
+		for metric := DEGREE; metric <= PAGERANK; metric++ {
+			attributes[metric.String()] = attribute.FormatDouble(node.Value(metric))
+		}
+		annotated.Statements = append(annotated.Statements, &parser.Node{ID: builder.ID(node.Node.Name), Attributes: []parser.AttributeMap{attributes}})
+	}
+	return annotated
+}
+
+// COLD_HUE and HOT_HUE are the hues of the nodes with the least and the
+// greatest value of the metric, blue and red
+const (
+	COLD_HUE = 2.0 / 3
+	HOT_HUE  = 0.0
+)
+
+// HeatMap returns graph with the nodes filled with a color going from blue
+// to red as the value of one of their metrics grows, in node statements at
+// the end of the graph, so that they override the colors of the nodes.
+// The value of the metric is written as the tooltip of each node.
+func HeatMap(graph parser.Graph, report Report, metric Metric) parser.Graph {
+	values := make([]float64, len(report.Nodes))
+	for i, node := range report.Nodes {
+		values[i] = node.Value(metric)
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	heatMap := graph
+	heatMap.Statements = append([]parser.Statement(nil), graph.Statements...)
+	for i, node := range report.Nodes {
+		scale := 0.0
+		if low, high := sorted[0], sorted[len(sorted)-1]; high > low {
+			scale = (values[i] - low) / (high - low)
+		}
+		hue := COLD_HUE + scale*(HOT_HUE-COLD_HUE)
+		attributes := parser.AttributeMap{
+			"style":     "filled",
+			"fillcolor": attribute.FormatColor(attribute.HSVToColor(hue, 0.6, 1)),
+			"tooltip":   metric.String() + " " + attribute.FormatDouble(values[i]),
+		}
+		heatMap.Statements = append(heatMap.Statements, &parser.Node{ID: builder.ID(node.Node.Name), Attributes: []parser.AttributeMap{attributes}})
+	}
+	return heatMap
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"dot-parser/internal/testutil"
+	"dot-parser/printer"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func near(first float64, second float64) bool {
+	return math.Abs(first-second) < 1e-9
+}
+
+func TestCompute(t *testing.T) {
+	report := Compute(testutil.ParseGraph(t, `graph { a -- b; b -- c; c -- a; c -- d }`), DefaultOptions())
+
+	if report.NodeCount != 4 || report.EdgeCount != 4 || report.Components != 1 || !near(report.Density, 2.0/3) {
+		t.Fatalf("Expected 4 nodes, 4 edges, 1 component and a density of 2/3, got %+v", report)
+	}
+	expectedDegrees := DegreeDistribution{Min: 1, Max: 3, Mean: 2, Counts: map[int]int{1: 1, 2: 2, 3: 1}}
+	if !reflect.DeepEqual(report.Degrees, expectedDegrees) {
+		t.Fatalf("Expected the degrees %+v, got %+v", expectedDegrees, report.Degrees)
+	}
+	if report.Diameter != 2 || !near(report.AveragePathLength, 4.0/3) || !near(report.Clustering, 7.0/12) {
+		t.Fatalf("Expected a diameter of 2, paths of 4/3 and a clustering of 7/12, got %d, %v and %v",
+			report.Diameter, report.AveragePathLength, report.Clustering)
+	}
+
+	expected := map[string][3]float64{
+		"a": {1, 0, 0.75},
+		"b": {1, 0, 0.75},
+		"c": {1.0 / 3, 2, 1},
+		"d": {0, 0, 0.6},
+	}
+	total := 0.0
+	for _, node := range report.Nodes {
+		values := expected[node.Node.Name]
+		if !near(node.Clustering, values[0]) || !near(node.Betweenness, values[1]) || !near(node.Closeness, values[2]) {
+			t.Fatalf("Expected %s to have the metrics %v, got %+v", node.Node.Name, values, node)
+		}
+		if node.Node.Name != "c" && node.PageRank >= report.Nodes[2].PageRank {
+			t.Fatalf("Expected c to have the greatest PageRank, got %+v", report.Nodes)
+		}
+		total += node.PageRank
+	}
+	if !near(total, 1) {
+		t.Fatalf("Expected the PageRanks to sum to 1, got %v", total)
+	}
+}
+
+func TestComputeDirected(t *testing.T) {
+	report := Compute(testutil.ParseGraph(t, `digraph { a -> b; b -> c; b -> c; d }`), DefaultOptions())
+
+	if report.Components != 2 || !near(report.Density, 0.25) || report.Diameter != 2 || !near(report.AveragePathLength, 4.0/3) {
+		t.Fatalf("Expected 2 components, a density of 1/4 and paths of 1 to 2 edges, got %+v", report)
+	}
+	if report.OutDegrees.Max != 2 || report.InDegrees.Max != 2 || report.Degrees.Max != 3 {
+		t.Fatalf("Expected the degrees of b and c, got %+v", report)
+	}
+	b := report.Nodes[1]
+	if b.InDegree != 1 || b.OutDegree != 2 || b.Degree != 3 || !near(b.Betweenness, 1) || !near(b.Closeness, 1.0/3) {
+		t.Fatalf("Expected the metrics of b, got %+v", b)
+	}
+	if c := report.Nodes[2]; c.Closeness != 0 || c.PageRank <= b.PageRank {
+		t.Fatalf("Expected c to reach no node and to have the greatest PageRank, got %+v", c)
+	}
+}
+
+func TestHeatMap(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph {
+	a -- b
+	b -- c
+}`)
+	report := Compute(graph, DefaultOptions())
+
+	expected := `graph {
+	a -- b
+	b -- c
+	a [fillcolor="#6666ff", style=filled, tooltip="degree 1"]
+	b [fillcolor="#ff6666", style=filled, tooltip="degree 2"]
+	c [fillcolor="#6666ff", style=filled, tooltip="degree 1"]
+}
+`
+	if printed := printer.Print(HeatMap(graph, report, DEGREE)).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+
+	annotated := printer.Print(Annotate(graph, report)).Unwrap()
+	if !strings.Contains(annotated, "\tb [betweenness=1, closeness=1, clustering=0, degree=2, indegree=1, outdegree=1, pagerank=") {
+		t.Fatalf("Expected the metrics of b, got:\n%s", annotated)
+	}
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	. "dot-parser/lexer"
+	"dot-parser/option"
+	"dot-parser/parser"
+	"strings"
+)
+
+// Graph is the resolved form of a parser.Graph: every node appears once, with
+// the node defaults in effect where it was first mentioned merged with the
+// attributes of all of its node statements, and every edge carries the edge
+// defaults in effect where it was declared. Nodes and edges are kept in
+// declaration order.
+type Graph struct {
+	IsStrict   bool
+	IsDirect   bool
+	Name       option.Option[string]
+	Attributes parser.AttributeMap
+	Nodes      []*Node
+	Edges      []*Edge
+	Subgraphs  []*Subgraph
+
+	nodes map[string]*Node
+	out   [][]*Edge
+	in    [][]*Edge
+}
+
+// Subgraph records the explicit graph attributes of a subgraph and the nodes
+// and edges declared directly inside it
+type Subgraph struct {
+	Name       option.Option[string]
+	Attributes parser.AttributeMap
+	Nodes      []*Node
+	Edges      []*Edge
+	Subgraphs  []*Subgraph
+
+	members map[*Node]bool
+}
+
+type Node struct {
+	Index      int
+	Name       string
+	Attributes parser.AttributeMap
+	Position   Position
+}
+
+type Edge struct {
+	Index      int
+	Tail       *Node
+	Head       *Node
+	TailPort   option.Option[string]
+	HeadPort   option.Option[string]
+	Attributes parser.AttributeMap
+	Position   Position
+	Statements []*parser.Edge
+}
+
+func (graph *Graph) Node(name string) option.Option[*Node] {
+	if node, exists := graph.nodes[name]; exists {
+		return option.Some(node)
+	}
+	return option.None[*Node]()
+}
+
+// Out returns the edges whose tail is node, in declaration order
+func (graph *Graph) Out(node *Node) []*Edge {
+	return graph.out[node.Index]
+}
+
+// In returns the edges whose head is node, in declaration order
+func (graph *Graph) In(node *Node) []*Edge {
+	return graph.in[node.Index]
+}
+
+// Opposite returns the endpoint of edge that is not node
+func (edge *Edge) Opposite(node *Node) *Node {
+	if edge.Tail == node {
+		return edge.Head
+	}
+	return edge.Tail
+}
+
+// AttributesWithPorts returns a copy of the attributes of edge where its
+// ports are the tailport and headport attributes
+func (edge *Edge) AttributesWithPorts() parser.AttributeMap {
+	attributes := make(parser.AttributeMap, len(edge.Attributes)+2)
+	for key, value := range edge.Attributes {
+		attributes[key] = value
+	}
+	if edge.TailPort.IsSome() {
+		attributes["tailport"] = edge.TailPort.Unwrap()
+	}
+	if edge.HeadPort.IsSome() {
+		attributes["headport"] = edge.HeadPort.Unwrap()
+	}
+	return attributes
+}
+
+// AllNodes returns the nodes of the subgraph and of its nested subgraphs
+func (subgraph *Subgraph) AllNodes() []*Node {
+	seen := make(map[*Node]bool)
+	var nodes []*Node
+	var visit func(*Subgraph)
+	visit = func(subgraph *Subgraph) {
+		for _, node := range subgraph.Nodes {
+			if !seen[node] {
+				seen[node] = true
+				nodes = append(nodes, node)
+			}
+		}
+		for _, child := range subgraph.Subgraphs {
+			visit(child)
+		}
+	}
+	visit(subgraph)
+	return nodes
+}
+
+// Placement maps every node declared in a subgraph to the deepest subgraph
+// declaring it, the first one in pre-order among equally deep ones. Only
+// the subgraphs for which keep is true are considered: the nodes of the
+// others are treated as declared in their closest kept ancestor, or in no
+// subgraph when there is none.
+func (graph *Graph) Placement(keep func(*Subgraph) bool) map[*Node]*Subgraph {
+	placement := make(map[*Node]*Subgraph)
+	depths := make(map[*Node]int)
+
+	var visit func([]*Subgraph, *Subgraph, int)
+	visit = func(subgraphs []*Subgraph, parent *Subgraph, depth int) {
+		for _, subgraph := range subgraphs {
+			owner, ownerDepth := parent, depth
+			if keep(subgraph) {
+				owner, ownerDepth = subgraph, depth+1
+			}
+
+			if owner != nil {
+				for _, node := range subgraph.Nodes {
+					if ownerDepth > depths[node] {
+						depths[node] = ownerDepth
+						placement[node] = owner
+					}
+				}
+			}
+			visit(subgraph.Subgraphs, owner, ownerDepth)
+		}
+	}
+	visit(graph.Subgraphs, nil, 0)
+
+	return placement
+}
+
+// IsCluster reports whether the subgraph is drawn as a cluster, i.e. its
+// name starts with "cluster"
+func (subgraph *Subgraph) IsCluster() bool {
+	return strings.HasPrefix(subgraph.Name.OrElse(""), "cluster")
+}
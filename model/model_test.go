@@ -0,0 +1,115 @@
+package model
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func resolveGraph(t *testing.T, input string) *Graph {
+	return Resolve(testutil.ParseGraph(t, input))
+}
+
+func TestResolveDefaults(t *testing.T) {
+	graph := resolveGraph(t, `digraph {
+		rankdir = LR
+		a
+		node [ shape = box ]
+		a -> b [ color = red ]
+		edge [ style = dashed ]
+		b -> c
+		b [ label = B ]
+	}`)
+
+	if graph.Attributes["rankdir"] != "LR" {
+		t.Fatalf("Expected graph attribute 'rankdir', got %v", graph.Attributes)
+	}
+
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Fatalf("Expected 3 nodes and 2 edges, got %v and %v", graph.Nodes, graph.Edges)
+	}
+
+	a, b := graph.Node("a").Unwrap(), graph.Node("b").Unwrap()
+	if _, hasShape := a.Attributes["shape"]; hasShape {
+		t.Fatalf("Expected node 'a' to be declared before the node defaults, got %v", a.Attributes)
+	}
+
+	if b.Attributes["shape"] != "box" || b.Attributes["label"] != "B" {
+		t.Fatalf("Expected node 'b' with shape 'box' and label 'B', got %v", b.Attributes)
+	}
+
+	first, second := graph.Edges[0], graph.Edges[1]
+	if first.Attributes["color"] != "red" || first.Attributes["style"] != "" || second.Attributes["style"] != "dashed" {
+		t.Fatalf("Expected edge defaults to apply only to later edges, got %v and %v", first.Attributes, second.Attributes)
+	}
+
+	if out := graph.Out(b); len(out) != 1 || out[0] != second {
+		t.Fatalf("Expected 'b -> c' to be the only edge out of 'b', got %v", out)
+	}
+
+	if in := graph.In(b); len(in) != 1 || in[0] != first {
+		t.Fatalf("Expected 'a -> b' to be the only edge into 'b', got %v", in)
+	}
+}
+
+func TestResolveStrict(t *testing.T) {
+	graph := resolveGraph(t, "strict graph { a -- b [ color = red ]; b -- a [ style = bold ] }")
+
+	if len(graph.Edges) != 1 {
+		t.Fatalf("Expected repeated edges to be merged, got %v", graph.Edges)
+	}
+
+	if edge := graph.Edges[0]; edge.Attributes["color"] != "red" || edge.Attributes["style"] != "bold" || len(edge.Statements) != 2 {
+		t.Fatalf("Expected merged edge with both attributes and statements, got %v", edge)
+	}
+}
+
+func TestResolveSubgraphs(t *testing.T) {
+	graph := resolveGraph(t, `digraph {
+		subgraph cluster_0 { node [ color = blue ]; label = x; a -> b; { c } }
+		d
+		subgraph cluster_0 { e }
+	}`)
+
+	if len(graph.Subgraphs) != 1 {
+		t.Fatalf("Expected subgraphs with the same name to be merged, got %v", graph.Subgraphs)
+	}
+
+	cluster := graph.Subgraphs[0]
+	if cluster.Attributes["label"] != "x" || len(cluster.Nodes) != 3 || len(cluster.Edges) != 1 || len(cluster.Subgraphs) != 1 {
+		t.Fatalf("Expected cluster with label, 3 nodes, 1 edge and 1 subgraph, got %#v", cluster)
+	}
+
+	if all := cluster.AllNodes(); len(all) != 4 {
+		t.Fatalf("Expected 4 nodes in the cluster and its subgraphs, got %v", all)
+	}
+
+	if c := graph.Node("c").Unwrap(); c.Attributes["color"] != "blue" {
+		t.Fatalf("Expected node defaults of the cluster to apply to nested subgraphs, got %v", c.Attributes)
+	}
+
+	if d := graph.Node("d").Unwrap(); d.Attributes["color"] != "" {
+		t.Fatalf("Expected node defaults of the cluster not to leak, got %v", d.Attributes)
+	}
+}
+
+func TestPlacement(t *testing.T) {
+	graph := resolveGraph(t, `graph {
+		subgraph cluster_a { a; b; { c } subgraph cluster_b { b } }
+		subgraph cluster_c { a; d }
+		e
+	}`)
+
+	named := func(subgraph *Subgraph) bool { return subgraph.Name.IsSome() }
+	placement := graph.Placement(named)
+
+	expected := map[string]string{"a": "cluster_a", "b": "cluster_b", "c": "cluster_a", "d": "cluster_c"}
+	for name, subgraph := range expected {
+		placed, exists := placement[graph.Node(name).Unwrap()]
+		if !exists || placed.Name.OrElse("") != subgraph {
+			t.Errorf("Expected node %s in %s, got %v", name, subgraph, placed)
+		}
+	}
+	if _, exists := placement[graph.Node("e").Unwrap()]; exists {
+		t.Errorf("Expected node e not to be in a subgraph")
+	}
+}
@@ -0,0 +1,194 @@
+package model
+
+import (
+	. "dot-parser/lexer"
+	"dot-parser/option"
+	"dot-parser/parser"
+)
+
+type edgeKey struct {
+	tail *Node
+	head *Node
+}
+
+type resolver struct {
+	graph     *Graph
+	subgraphs map[string]*Subgraph
+	edges     map[edgeKey]*Edge
+}
+
+// scope holds the defaults in effect while resolving a statement list.
+// Subgraphs start from a copy of the defaults of their parent.
+type scope struct {
+	nodeDefaults parser.AttributeMap
+	edgeDefaults parser.AttributeMap
+	attributes   parser.AttributeMap
+	subgraph     option.Option[*Subgraph]
+}
+
+// Resolve builds the resolved graph model of a parsed graph. Subgraphs with
+// the same name are merged, as Graphviz does, and in strict graphs repeated
+// edges between the same nodes are merged into the first one.
+func Resolve(graph parser.Graph) *Graph {
+	resolved := &Graph{
+		IsStrict:   graph.IsStrict,
+		IsDirect:   graph.IsDirect,
+		Name:       graph.Name,
+		Attributes: parser.AttributeMap{},
+		nodes:      make(map[string]*Node),
+	}
+
+	r := resolver{
+		graph:     resolved,
+		subgraphs: make(map[string]*Subgraph),
+		edges:     make(map[edgeKey]*Edge),
+	}
+	r.resolve(graph.Statements, scope{
+		nodeDefaults: parser.AttributeMap{},
+		edgeDefaults: parser.AttributeMap{},
+		attributes:   resolved.Attributes,
+		subgraph:     option.None[*Subgraph](),
+	})
+
+	return resolved
+}
+
+func (r *resolver) resolve(stmts []parser.Statement, current scope) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *parser.Node:
+			node := r.declare(stmt.ID.Name, stmt.Position, current)
+			for _, attributeMap := range stmt.Attributes {
+				merge(node.Attributes, attributeMap)
+			}
+		case *parser.Edge:
+			tail := r.declare(stmt.Lnode.Name, stmt.Position, current)
+			head := r.declare(stmt.Rnode.Name, stmt.Position, current)
+			r.addEdge(stmt, tail, head, current)
+		case *parser.AttributeStmt:
+			for _, attributeMap := range stmt.Attributes {
+				switch stmt.Level {
+				case parser.GRAPH_LEVEL:
+					merge(current.attributes, attributeMap)
+				case parser.NODE_LEVEL:
+					merge(current.nodeDefaults, attributeMap)
+				case parser.EDGE_LEVEL:
+					merge(current.edgeDefaults, attributeMap)
+				}
+			}
+		case *parser.SingleAttribute:
+			current.attributes[stmt.Key] = stmt.Value
+		case *parser.Subgraph:
+			subgraph := r.subgraph(stmt.Name, current)
+			r.resolve(stmt.Statements, scope{
+				nodeDefaults: copyMap(current.nodeDefaults),
+				edgeDefaults: copyMap(current.edgeDefaults),
+				attributes:   subgraph.Attributes,
+				subgraph:     option.Some(subgraph),
+			})
+		}
+	}
+}
+
+func (r *resolver) declare(name string, position Position, current scope) *Node {
+	node, exists := r.graph.nodes[name]
+	if !exists {
+		node = &Node{
+			Index:      len(r.graph.Nodes),
+			Name:       name,
+			Attributes: copyMap(current.nodeDefaults),
+			Position:   position,
+		}
+		r.graph.nodes[name] = node
+		r.graph.Nodes = append(r.graph.Nodes, node)
+		r.graph.out = append(r.graph.out, nil)
+		r.graph.in = append(r.graph.in, nil)
+	}
+
+	if current.subgraph.IsSome() {
+		current.subgraph.Unwrap().addNode(node)
+	}
+	return node
+}
+
+func (r *resolver) addEdge(stmt *parser.Edge, tail *Node, head *Node, current scope) {
+	attributes := copyMap(current.edgeDefaults)
+	for _, attributeMap := range stmt.Attributes {
+		merge(attributes, attributeMap)
+	}
+
+	key := edgeKey{tail: tail, head: head}
+	if !r.graph.IsDirect && head.Index < tail.Index {
+		key = edgeKey{tail: head, head: tail}
+	}
+
+	if existing, exists := r.edges[key]; exists && r.graph.IsStrict {
+		merge(existing.Attributes, attributes)
+		existing.Statements = append(existing.Statements, stmt)
+		return
+	}
+
+	edge := &Edge{
+		Index:      len(r.graph.Edges),
+		Tail:       tail,
+		Head:       head,
+		TailPort:   stmt.Lnode.Port,
+		HeadPort:   stmt.Rnode.Port,
+		Attributes: attributes,
+		Position:   stmt.Position,
+		Statements: []*parser.Edge{stmt},
+	}
+	r.edges[key] = edge
+	r.graph.Edges = append(r.graph.Edges, edge)
+	r.graph.out[tail.Index] = append(r.graph.out[tail.Index], edge)
+	r.graph.in[head.Index] = append(r.graph.in[head.Index], edge)
+
+	if current.subgraph.IsSome() {
+		subgraph := current.subgraph.Unwrap()
+		subgraph.Edges = append(subgraph.Edges, edge)
+	}
+}
+
+func (r *resolver) subgraph(name option.Option[string], current scope) *Subgraph {
+	if name.IsSome() {
+		if existing, exists := r.subgraphs[name.Unwrap()]; exists {
+			return existing
+		}
+	}
+
+	subgraph := &Subgraph{
+		Name:       name,
+		Attributes: parser.AttributeMap{},
+		members:    make(map[*Node]bool),
+	}
+	if name.IsSome() {
+		r.subgraphs[name.Unwrap()] = subgraph
+	}
+
+	if current.subgraph.IsSome() {
+		parent := current.subgraph.Unwrap()
+		parent.Subgraphs = append(parent.Subgraphs, subgraph)
+	} else {
+		r.graph.Subgraphs = append(r.graph.Subgraphs, subgraph)
+	}
+	return subgraph
+}
+
+func (subgraph *Subgraph) addNode(node *Node) {
+	if !subgraph.members[node] {
+		subgraph.members[node] = true
+		subgraph.Nodes = append(subgraph.Nodes, node)
+	}
+}
+
+func merge(target parser.AttributeMap, source parser.AttributeMap) {
+	for key, value := range source {
+		target[key] = value
+	}
+}
+
+func copyMap(attributes parser.AttributeMap) parser.AttributeMap {
+	out_map := make(parser.AttributeMap, len(attributes))
+	merge(out_map, attributes)
+	return out_map
+}
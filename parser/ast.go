@@ -1,6 +1,10 @@
 package parser
 
-import "dot-parser/option"
+import (
+	. "dot-parser/lexer"
+	"dot-parser/option"
+	"sort"
+)
 
 type Graph struct {
 	IsStrict   bool
@@ -18,6 +22,7 @@ type AttributeMap map[string]string
 type Node struct {
 	ID         NodeID
 	Attributes []AttributeMap
+	Position   Position
 }
 
 type NodeID struct {
@@ -36,6 +41,7 @@ type Edge struct {
 	Lnode      NodeID
 	Rnode      NodeID
 	Attributes []AttributeMap
+	Position   Position
 }
 
 type AttributeLevel uint8
@@ -49,17 +55,26 @@ const (
 type AttributeStmt struct {
 	Level      AttributeLevel
 	Attributes []AttributeMap
+	Position   Position
 }
 
 type SingleAttribute struct {
-	Key   string
-	Value string
+	Key      string
+	Value    string
+	Position Position
+}
+
+type Subgraph struct {
+	Name       option.Option[string]
+	Statements []Statement
+	Position   Position
 }
 
 func (n *Node) isStatement() bool            { return true }
 func (e *Edge) isStatement() bool            { return true }
 func (a *AttributeStmt) isStatement() bool   { return true }
 func (a *SingleAttribute) isStatement() bool { return true }
+func (s *Subgraph) isStatement() bool        { return true }
 
 func (attrs AttributeMap) String() string {
 	var out_string string
@@ -69,6 +84,16 @@ func (attrs AttributeMap) String() string {
 	return "[ " + out_string + "]"
 }
 
+// Keys returns the keys of the map in sorted order
+func (attrs AttributeMap) Keys() []string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (node NodeID) String() string {
 	return node.Name + ":" + node.Port.OrElse("/")
 }
@@ -28,7 +28,7 @@ func parseGraph(iter TokenIterator) Result[parserData[Graph]] {
 		return parse(iter,
 			keep(&name, optional(matchToken(ID), []Token{ID})),
 			skip(matchToken(OPEN_BRACE)),
-			keep(&stmts, list(parseStmtInList, []Token{ID, GRAPH, NODE, EDGE})),
+			keep(&stmts, list(parseStmtInList, stmtFirstTokens)),
 			skip(matchToken(CLOSE_BRACE)),
 			skip(matchToken(EOF)),
 		)
@@ -47,6 +47,8 @@ func parseGraph(iter TokenIterator) Result[parserData[Graph]] {
 	})
 }
 
+var stmtFirstTokens = []Token{ID, GRAPH, NODE, EDGE, SUBGRAPH, OPEN_BRACE}
+
 // StatementInList: Statement ';'?
 func parseStmtInList(iter TokenIterator, isDirect bool) Result[parserData[[]Statement]] {
 	var stmt []Statement
@@ -59,7 +61,7 @@ func parseStmtInList(iter TokenIterator, isDirect bool) Result[parserData[[]Stat
 	return makeParserDataRes(newIter, stmt)
 }
 
-// Statement(isDirect bool): NodeStatement | EdgeStatement(isDirect) | AttributeStatement | SingleAttributeStatement
+// Statement(isDirect bool): NodeStatement | EdgeStatement(isDirect) | AttributeStatement | SingleAttributeStatement | Subgraph(isDirect)
 func parseStmt(iter TokenIterator, isDirect bool) Result[parserData[[]Statement]] {
 	var stmt []Statement
 
@@ -69,11 +71,13 @@ func parseStmt(iter TokenIterator, isDirect bool) Result[parserData[[]Statement]
 			var attrib SingleAttribute
 			newIter = parse(iter, keep(&attrib, parseAttribute))
 			stmt = []Statement{&attrib}
-		} else if peekToken(2, ARC, DIRECTED_ARC)(iter) || peekToken(4, ARC, DIRECTED_ARC)(iter) {
+		} else if peekToken(2, ARC, DIRECTED_ARC)(iter) || (peekToken(2, COLON)(iter) && peekToken(4, ARC, DIRECTED_ARC)(iter)) {
 			newIter = parse(iter, keep(&stmt, partialApply(isDirect, parseEdgeStmt)))
 		} else {
 			newIter = parse(iter, keep(&stmt, parseNodeStmt))
 		}
+	} else if peekToken(1, SUBGRAPH, OPEN_BRACE)(iter) {
+		newIter = parse(iter, keep(&stmt, partialApply(isDirect, parseSubgraph)))
 	} else {
 		newIter = parse(iter, keep(&stmt, parseAttrStmt))
 	}
@@ -81,6 +85,46 @@ func parseStmt(iter TokenIterator, isDirect bool) Result[parserData[[]Statement]
 	return makeParserDataRes(newIter, stmt)
 }
 
+// Subgraph(isDirect bool): (SUBGRAPH ID?)? '{' StatementInList(isDirect)* '}'
+func parseSubgraph(iter TokenIterator, isDirect bool) Result[parserData[[]Statement]] {
+	var pos Position
+	var keyword option.Option[TokenData]
+	var name option.Option[TokenData]
+	var stmts [][]Statement
+
+	parseStmtInList := partialApply(isDirect, parseStmtInList)
+	newIter := parse(iter,
+		position(&pos),
+		keep(&keyword, optional(matchToken(SUBGRAPH), []Token{SUBGRAPH})),
+	)
+
+	if keyword.IsSome() {
+		newIter = FlatMap(newIter, func(iter TokenIterator) Result[TokenIterator] {
+			return parse(iter, keep(&name, optional(matchToken(ID), []Token{ID})))
+		})
+	}
+
+	newIter = FlatMap(newIter, func(iter TokenIterator) Result[TokenIterator] {
+		return parse(iter,
+			skip(matchToken(OPEN_BRACE)),
+			keep(&stmts, list(parseStmtInList, stmtFirstTokens)),
+			skip(matchToken(CLOSE_BRACE)),
+		)
+	})
+
+	var subgraphStmts []Statement
+	for _, stmts := range stmts {
+		subgraphStmts = append(subgraphStmts, stmts...)
+	}
+
+	subgraph := Subgraph{
+		Name:       option.Map(name, func(token TokenData) string { return string(token.Lexeme()) }),
+		Statements: subgraphStmts,
+		Position:   pos,
+	}
+	return makeParserDataRes(newIter, []Statement{&subgraph})
+}
+
 // AttributeStatement: (GRAPH | NODE | EDGE) AttributeList*
 func parseAttrStmt(iter TokenIterator) Result[parserData[[]Statement]] {
 	var attrType TokenData
@@ -101,17 +145,17 @@ func parseAttrStmt(iter TokenIterator) Result[parserData[[]Statement]] {
 		level = GRAPH_LEVEL
 	}
 
-	attribute := AttributeStmt{Level: level, Attributes: attrList}
+	attribute := AttributeStmt{Level: level, Attributes: attrList, Position: attrType.Position()}
 	return makeParserDataRes(newIter, []Statement{&attribute})
 }
 
 // EdgeStatement(isDirect bool): NodeId EdgeRHS(isDirect)+ AttributeList*
 func parseEdgeStmt(iter TokenIterator, isDirect bool) Result[parserData[[]Statement]] {
 	var firstLhs NodeID
-	var nodes []NodeID
+	var nodes []positioned[NodeID]
 	var attributes []AttributeMap
 
-	parseEdgeRhs := partialApply(isDirect, parseEdgeRhs)
+	parseEdgeRhs := withPosition(partialApply(isDirect, parseEdgeRhs))
 
 	newIter := parse(iter,
 		keep(&firstLhs, parseNodeID),
@@ -119,21 +163,20 @@ func parseEdgeStmt(iter TokenIterator, isDirect bool) Result[parserData[[]Statem
 		keep(&attributes, list(parseAttrList, []Token{OPEN_SQUARE_BRACKET})),
 	)
 
-	var edges []Statement
-	firstRhs, nodes := nodes[0], nodes[1:]
-	edges = append(edges, &Edge{
-		Lnode:      firstLhs,
-		Rnode:      firstRhs,
-		Attributes: attributes,
-	})
+	if newIter.IsErr() {
+		return Err[parserData[[]Statement]](newIter.UnwrapErr())
+	}
 
+	var edges []Statement
+	lhs := firstLhs
 	for _, node := range nodes {
 		edges = append(edges, &Edge{
-			Lnode:      firstRhs,
-			Rnode:      node,
+			Lnode:      lhs,
+			Rnode:      node.value,
 			Attributes: attributes,
+			Position:   node.position,
 		})
-		firstRhs = node
+		lhs = node.value
 	}
 	return makeParserDataRes(newIter, edges)
 }
@@ -168,15 +211,17 @@ func parseEdgeRhs(iter TokenIterator, isDirect bool) Result[parserData[NodeID]]
 
 // NodeStatement: NodeId AttributeList*
 func parseNodeStmt(iter TokenIterator) Result[parserData[[]Statement]] {
+	var pos Position
 	var nodeID NodeID
 	var attrList []AttributeMap
 
 	newIter := parse(iter,
+		position(&pos),
 		keep(&nodeID, parseNodeID),
 		keep(&attrList, list(parseAttrList, []Token{OPEN_SQUARE_BRACKET})),
 	)
 
-	node := Node{ID: nodeID, Attributes: attrList}
+	node := Node{ID: nodeID, Attributes: attrList, Position: pos}
 	return makeParserDataRes(newIter, []Statement{&node})
 }
 
@@ -219,8 +264,9 @@ func parseAttribute(iter TokenIterator) Result[parserData[SingleAttribute]] {
 	)
 
 	return makeParserDataRes(newIter, SingleAttribute{
-		Key:   string(firstId.Lexeme()),
-		Value: string(secondId.Lexeme()),
+		Key:      string(firstId.Lexeme()),
+		Value:    string(secondId.Lexeme()),
+		Position: firstId.Position(),
 	})
 }
 
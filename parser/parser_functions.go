@@ -104,6 +104,34 @@ func nonEmptyList[T any](fn func(TokenIterator) Result[parserData[T]], expectedT
 	}
 }
 
+// position stores the position of the next token without consuming it
+func position(pointer *Position) func(TokenIterator) Result[TokenIterator] {
+	return func(iter TokenIterator) Result[TokenIterator] {
+		if token := iter.Peek(); token.IsSome() && token.Unwrap().IsOk() {
+			*pointer = token.Unwrap().Unwrap().Position()
+		}
+		return Ok(iter)
+	}
+}
+
+type positioned[T any] struct {
+	value    T
+	position Position
+}
+
+func withPosition[T any](fn func(TokenIterator) Result[parserData[T]]) func(TokenIterator) Result[parserData[positioned[T]]] {
+	return func(iter TokenIterator) Result[parserData[positioned[T]]] {
+		var pos Position
+		var value T
+		newIter := parse(iter,
+			position(&pos),
+			keep(&value, fn),
+		)
+
+		return makeParserDataRes(newIter, positioned[T]{value: value, position: pos})
+	}
+}
+
 func matchToken(expectedTokens ...Token) func(TokenIterator) Result[parserData[TokenData]] {
 	return func(iter TokenIterator) Result[parserData[TokenData]] {
 		token := iter.Next().Unwrap()
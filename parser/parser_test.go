@@ -61,7 +61,7 @@ func TestParseAttribute(t *testing.T) {
 
 	attribute := res.Unwrap().value
 	if attribute.Key != "first" || attribute.Value != "second" {
-		t.Fatalf("Expected Attribute with key 'first' and value 'second', found %s", attribute)
+		t.Fatalf("Expected Attribute with key 'first' and value 'second', found %v", attribute)
 	}
 }
 
@@ -209,6 +209,14 @@ func TestParseEdgeStmt(t *testing.T) {
 	}
 }
 
+func TestParseEdgeStmtWithoutRhs(t *testing.T) {
+	iter := makeParser("NodeId0 -- [ class = test ]")
+
+	if res := parseEdgeStmt(iter, false); res.IsOk() {
+		t.Fatalf("Expected error for Edge Statement without right hand side, got %v", res.Unwrap().value)
+	}
+}
+
 func TestParseGraphAttrStmt(t *testing.T) {
 	iter := makeParser("graph []")
 
@@ -396,3 +404,70 @@ func TestParseIndirectGraph(t *testing.T) {
 		t.Fatalf("Expected Graph with 0 statements, got %#v", graph)
 	}
 }
+
+func TestParseStatementPositions(t *testing.T) {
+	iter := makeParser("digraph {\n  a [ color = red ]\n  a -> b -> c\n  rankdir = LR\n}")
+
+	res := parseGraph(iter)
+	if res.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", res.UnwrapErr())
+	}
+
+	stmts := res.Unwrap().value.Statements
+	if len(stmts) != 4 {
+		t.Fatalf("Expected Graph with 4 statements, got %#v", stmts)
+	}
+
+	if node := stmts[0].(*Node); node.Position.Line() != 2 {
+		t.Fatalf("Expected Node Statement on line 2, got %v", node.Position)
+	}
+
+	first, second := stmts[1].(*Edge), stmts[2].(*Edge)
+	if first.Position.Line() != 3 || second.Position.Line() != 3 || first.Position.Column() >= second.Position.Column() {
+		t.Fatalf("Expected Edges on line 3 positioned at their arcs, got %v and %v", first.Position, second.Position)
+	}
+
+	if attr := stmts[3].(*SingleAttribute); attr.Position.Line() != 4 {
+		t.Fatalf("Expected Single Attribute on line 4, got %v", attr.Position)
+	}
+}
+
+func TestParseSubgraph(t *testing.T) {
+	iter := makeParser("subgraph cluster_0 { color = blue; a -> b; { rank = same; c d } }")
+
+	res := parseStmt(iter, true)
+	if res.IsErr() {
+		t.Fatalf("Expected Subgraph, failed with %s", res.UnwrapErr())
+	}
+
+	subgraph, isSubgraph := res.Unwrap().value[0].(*Subgraph)
+	if !isSubgraph {
+		t.Fatalf("Expected Subgraph, but got %v", res.Unwrap().value[0])
+	}
+
+	if subgraph.Name.IsNone() || subgraph.Name.Unwrap() != "cluster_0" {
+		t.Fatalf("Expected Subgraph with name 'cluster_0', got %#v", subgraph)
+	}
+
+	if len(subgraph.Statements) != 3 {
+		t.Fatalf("Expected Subgraph with 3 statements, got %#v", subgraph.Statements)
+	}
+
+	anonymous, isSubgraph := subgraph.Statements[2].(*Subgraph)
+	if !isSubgraph || anonymous.Name.IsSome() || len(anonymous.Statements) != 3 {
+		t.Fatalf("Expected anonymous Subgraph with 3 statements, got %#v", subgraph.Statements[2])
+	}
+}
+
+func TestParseGraphWithSubgraph(t *testing.T) {
+	iter := makeParser("graph { subgraph { a } b -- c }")
+
+	res := parseGraph(iter)
+	if res.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", res.UnwrapErr())
+	}
+
+	if stmts := res.Unwrap().value.Statements; len(stmts) != 2 {
+		t.Fatalf("Expected Graph with 2 statements, got %#v", stmts)
+	}
+}
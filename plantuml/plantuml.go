@@ -0,0 +1,330 @@
+package plantuml
+
+import (
+	"dot-parser/alias"
+	"dot-parser/attribute"
+	"dot-parser/model"
+	"dot-parser/parser"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var keywords = []string{
+	"as", "left", "right", "up", "down", "to", "direction", "title", "skinparam",
+	"together", "hide", "show", "remove", "note", "end", "legend", "header",
+	"footer", "caption", "scale", "actor", "agent", "artifact", "boundary",
+	"card", "circle", "cloud", "collections", "component", "control",
+	"database", "entity", "file", "folder", "frame", "hexagon", "interface",
+	"label", "node", "package", "person", "queue", "rectangle", "stack",
+	"storage", "usecase",
+}
+
+// elements maps Graphviz node shapes to PlantUML elements
+var elements = map[string]string{
+	"box":          "rectangle",
+	"rect":         "rectangle",
+	"rectangle":    "rectangle",
+	"square":       "rectangle",
+	"ellipse":      "usecase",
+	"oval":         "usecase",
+	"circle":       "circle",
+	"point":        "circle",
+	"doublecircle": "circle",
+	"cylinder":     "database",
+	"hexagon":      "hexagon",
+	"folder":       "folder",
+	"tab":          "folder",
+	"note":         "file",
+	"component":    "component",
+	"box3d":        "node",
+	"plaintext":    "label",
+	"plain":        "label",
+	"none":         "label",
+}
+
+// DEFAULT_ELEMENT is the element of nodes without a shape, Graphviz draws
+// them as ellipses
+const DEFAULT_ELEMENT = "usecase"
+
+type exporter struct {
+	graph   *model.Graph
+	aliases *alias.Aliases
+	builder strings.Builder
+}
+
+// Export writes a graph as a PlantUML deployment diagram.
+//
+// The graph label becomes the title, and rankdir=LR or RL the left to right
+// direction. Nodes become the element closest to their shape, an ellipse
+// when they have none and a rectangle when no element is close, with their
+// label and their fill, line and text colors; rounded rectangles become
+// cards. Clusters become rectangles holding their nodes and clusters, other
+// subgraphs are flattened. Edges become arrows for directed graphs, or
+// according to dir, with their label, color, thickness and dashed, dotted,
+// bold or invisible style. IDs that are not identifiers, or are PlantUML
+// keywords, are replaced by aliases; the other attributes and ports are left
+// out.
+func Export(graph parser.Graph) string {
+	return ExportModel(model.Resolve(graph))
+}
+
+func ExportModel(graph *model.Graph) string {
+	e := exporter{graph: graph, aliases: alias.New(keywords...)}
+	for _, node := range graph.Nodes {
+		e.aliases.Get(node.Name)
+	}
+
+	e.builder.WriteString("@startuml\n")
+	e.header()
+	placement := graph.Placement((*model.Subgraph).IsCluster)
+	e.nodes(placement, nil, "")
+	e.clusters(graph.Subgraphs, placement, "")
+	for _, edge := range graph.Edges {
+		e.edge(edge)
+	}
+	e.builder.WriteString("@enduml\n")
+	return e.builder.String()
+}
+
+// text writes a label as a quoted PlantUML string, with a \n for each line
+// break
+func text(label string) string {
+	lines := attribute.SplitLines(label)
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "\"", "<U+0022>")
+	}
+	return "\"" + strings.Join(lines, "\\n") + "\""
+}
+
+// color formats a Graphviz color as an hexadecimal PlantUML color, without
+// the leading #
+func color(value string) (string, bool) {
+	color := attribute.ParseColor(value)
+	if color.IsErr() {
+		return "", false
+	}
+	return strings.TrimPrefix(attribute.FormatColor(color.Unwrap()), "#"), true
+}
+
+func (e *exporter) header() {
+	if label, exists := e.graph.Attributes["label"]; exists {
+		label = attribute.ExpandEscString(label, attribute.EscContext{Graph: e.graph.Name.OrElse("")})
+		e.builder.WriteString("title " + strings.Join(attribute.SplitLines(label), "\\n") + "\n")
+	}
+	if rankdir := e.graph.Attributes["rankdir"]; rankdir == "LR" || rankdir == "RL" {
+		e.builder.WriteString("left to right direction\n")
+	}
+}
+
+// nodes writes the nodes placed in subgraph, or in no cluster when nil
+func (e *exporter) nodes(placement map[*model.Node]*model.Subgraph, subgraph *model.Subgraph, indent string) {
+	for _, node := range e.graph.Nodes {
+		if placement[node] == subgraph {
+			e.builder.WriteString(indent + e.node(node) + "\n")
+		}
+	}
+}
+
+func (e *exporter) node(node *model.Node) string {
+	id := e.aliases.Get(node.Name)
+
+	label := node.Name
+	if value, exists := node.Attributes["label"]; exists {
+		label = attribute.ExpandEscString(value, attribute.EscContext{Graph: e.graph.Name.OrElse(""), Node: node.Name})
+	}
+	element := DEFAULT_ELEMENT
+	if mapped, exists := elements[node.Attributes["shape"]]; exists {
+		element = mapped
+	} else if _, exists := node.Attributes["shape"]; exists {
+		element = "rectangle"
+	}
+
+	style := makeStyle(node.Attributes)
+	if style.rounded && element == "rectangle" {
+		element = "card"
+	}
+
+	line := element + " " + id
+	if label != id {
+		line = element + " " + text(label) + " as " + id
+	}
+	if colors := style.colors(); colors != "" {
+		line += " " + colors
+	}
+	return line
+}
+
+func (e *exporter) clusters(subgraphs []*model.Subgraph, placement map[*model.Node]*model.Subgraph, indent string) {
+	for _, subgraph := range subgraphs {
+		if !subgraph.IsCluster() {
+			e.clusters(subgraph.Subgraphs, placement, indent)
+			continue
+		}
+
+		name := subgraph.Name.Unwrap()
+		id := e.aliases.Unique(name)
+
+		// Graphviz shows no title for clusters without a label
+		label := " "
+		if value, exists := subgraph.Attributes["label"]; exists {
+			label = attribute.ExpandEscString(value, attribute.EscContext{Graph: name})
+		}
+		style := makeStyle(subgraph.Attributes)
+		if bgcolor, ok := color(subgraph.Attributes["bgcolor"]); ok && style.fill == "" {
+			style.fill = bgcolor
+		}
+		if pencolor, ok := color(subgraph.Attributes["pencolor"]); ok {
+			style.line = pencolor
+		}
+
+		line := indent + "rectangle " + text(label) + " as " + id
+		if colors := style.colors(); colors != "" {
+			line += " " + colors
+		}
+		e.builder.WriteString(line + " {\n")
+		e.nodes(placement, subgraph, indent+"  ")
+		e.clusters(subgraph.Subgraphs, placement, indent+"  ")
+		e.builder.WriteString(indent + "}\n")
+	}
+}
+
+func (e *exporter) edge(edge *model.Edge) {
+	tail, head := edge.Tail, edge.Head
+	dir := "none"
+	if e.graph.IsDirect {
+		dir = "forward"
+	}
+	if value := edge.Attributes["dir"]; value == "forward" || value == "back" || value == "both" || value == "none" {
+		dir = value
+	}
+	arrowhead, arrowtail := arrowHead(edge.Attributes["arrowhead"]), arrowHead(edge.Attributes["arrowtail"])
+
+	var tailEnd, headEnd string
+	switch dir {
+	case "forward":
+		headEnd = arrowhead
+	case "back":
+		tail, head = head, tail
+		headEnd = arrowtail
+	case "both":
+		tailEnd, headEnd = arrowtail, arrowhead
+	}
+	switch tailEnd {
+	case ">":
+		tailEnd = "<"
+	case "o", "*":
+	default:
+		tailEnd = ""
+	}
+
+	var options []string
+	if value, ok := color(edge.Attributes["color"]); ok {
+		options = append(options, "#"+value)
+	}
+	if items := attribute.ParseStyle(edge.Attributes["style"]); items.IsOk() {
+		for _, item := range items.Unwrap() {
+			switch item.Name {
+			case "dashed", "dotted", "bold":
+				options = append(options, item.Name)
+			case "invis":
+				options = append(options, "hidden")
+			}
+		}
+	}
+	if width := attribute.ParseDouble(edge.Attributes["penwidth"]); width.IsOk() {
+		options = append(options, fmt.Sprintf("thickness=%d", int(math.Max(1, math.Round(width.Unwrap())))))
+	}
+
+	arrow := tailEnd + "--" + headEnd
+	if len(options) > 0 {
+		arrow = tailEnd + "-[" + strings.Join(options, ",") + "]-" + headEnd
+	}
+
+	line := e.aliases.Get(tail.Name) + " " + arrow + " " + e.aliases.Get(head.Name)
+	if value, exists := edge.Attributes["label"]; exists {
+		label := attribute.ExpandEscString(value, attribute.EscContext{
+			Graph:    e.graph.Name.OrElse(""),
+			Tail:     edge.Tail.Name,
+			Head:     edge.Head.Name,
+			IsDirect: e.graph.IsDirect,
+		})
+		line += " : " + strings.Join(attribute.SplitLines(label), "\\n")
+	}
+	e.builder.WriteString(line + "\n")
+}
+
+// arrowHead maps the arrow shapes to the PlantUML arrow heads, an arrow when
+// there is no close one
+func arrowHead(value string) string {
+	switch value {
+	case "none":
+		return ""
+	case "dot", "odot":
+		return "o"
+	case "diamond", "odiamond", "box", "obox":
+		return "*"
+	default:
+		return ">"
+	}
+}
+
+// style holds the colors of a node or cluster
+type style struct {
+	fill      string
+	line      string
+	text      string
+	rounded   bool
+	lineStyle string
+}
+
+func makeStyle(attributes parser.AttributeMap) style {
+	var s style
+	var filled bool
+	if items := attribute.ParseStyle(attributes["style"]); items.IsOk() {
+		for _, item := range items.Unwrap() {
+			switch item.Name {
+			case "filled":
+				filled = true
+			case "rounded":
+				s.rounded = true
+			case "dashed", "dotted", "bold":
+				s.lineStyle = item.Name
+			}
+		}
+	}
+
+	s.line, _ = color(attributes["color"])
+	s.text, _ = color(attributes["fontcolor"])
+	if filled {
+		var ok bool
+		if s.fill, ok = color(attributes["fillcolor"]); !ok {
+			s.fill = s.line
+		}
+		if s.fill == "" {
+			s.fill = "d3d3d3"
+		}
+	}
+	return s
+}
+
+// colors writes the style as "#fill;line:color;line.style;text:color"
+func (s style) colors() string {
+	var parts []string
+	if s.line != "" {
+		parts = append(parts, "line:"+s.line)
+	}
+	if s.lineStyle != "" {
+		parts = append(parts, "line."+s.lineStyle)
+	}
+	if s.text != "" {
+		parts = append(parts, "text:"+s.text)
+	}
+	if s.fill != "" {
+		parts = append([]string{s.fill}, parts...)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(parts, ";")
+}
@@ -0,0 +1,66 @@
+package plantuml
+
+import (
+	"dot-parser/internal/testutil"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	graph := testutil.ParseGraph(t, `digraph {
+		rankdir=LR
+		label="Flow"
+		node [shape=box]
+		start [shape=circle, label="Start\nhere"]
+		"end" [shape=cylinder, style=filled, fillcolor="#ff0000", fontcolor=white]
+		subgraph cluster_0 {
+			label="Phase \"1\""
+			bgcolor=lightgrey
+			x -> y [style=dashed, label="go"]
+			subgraph cluster_inner { "x y" [style=rounded] }
+		}
+		{ z }
+		start -> x [color=blue, penwidth=2]
+		y -> "end" [dir=both, arrowtail=dot]
+		z -> "x y" [dir=back]
+	}`)
+
+	expected := `@startuml
+title Flow
+left to right direction
+circle "Start\nhere" as start
+database "end" as end_1 #ff0000;text:ffffff
+rectangle z
+rectangle "Phase <U+0022>1<U+0022>" as cluster_0 #d3d3d3 {
+  rectangle x
+  rectangle y
+  rectangle " " as cluster_inner {
+    card "x y" as x_y
+  }
+}
+x -[dashed]-> y : go
+start -[#0000ff,thickness=2]-> x
+y o--> end_1
+x_y --> z
+@enduml
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
+
+func TestExportUndirected(t *testing.T) {
+	graph := testutil.ParseGraph(t, `graph { a -- b; b -- c [dir=forward, arrowhead=diamond]; c -- a [style=invis] }`)
+
+	expected := `@startuml
+usecase a
+usecase b
+usecase c
+a -- b
+b --* c
+c -[hidden]- a
+@enduml
+`
+	if exported := Export(graph); exported != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, exported)
+	}
+}
@@ -0,0 +1,227 @@
+package printer
+
+import (
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var numeralPattern = regexp.MustCompile(`^-?(\.[0-9]+|[0-9]+(\.[0-9]*)?)$`)
+
+var keywords = map[string]bool{
+	"graph":    true,
+	"digraph":  true,
+	"strict":   true,
+	"node":     true,
+	"edge":     true,
+	"subgraph": true,
+}
+
+// NeedsQuotes reports whether id must be quoted to be read back as a single
+// ID: anything but a non-keyword alphanumeric identifier or a numeral
+func NeedsQuotes(id string) bool {
+	if id == "" || keywords[strings.ToLower(id)] {
+		return true
+	}
+	if numeralPattern.MatchString(id) {
+		return false
+	}
+
+	for i, char := range id {
+		if !(char == '_' || unicode.IsLetter(char) || (i > 0 && unicode.IsDigit(char))) {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote writes id as a quoted string, escaping its quotes
+func Quote(id string) string {
+	return "\"" + strings.ReplaceAll(id, "\"", "\\\"") + "\""
+}
+
+// ID writes id as it has to appear in DOT source, quoting it only when required
+func ID(id string) string {
+	if NeedsQuotes(id) {
+		return Quote(id)
+	}
+	return id
+}
+
+// NodeID writes a node ID with its optional port
+func NodeID(node parser.NodeID) string {
+	if node.Port.IsSome() {
+		return ID(node.Name) + ":" + ID(node.Port.Unwrap())
+	}
+	return ID(node.Name)
+}
+
+// AttributeList writes an attribute map as "[key=value, ...]" with sorted keys
+func AttributeList(attributes parser.AttributeMap) string {
+	keys := attributes.Keys()
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = ID(key) + "=" + ID(attributes[key])
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// PrintError is returned for IDs that cannot be read from DOT source
+type PrintError struct {
+	ID string
+}
+
+func (err *PrintError) Error() string {
+	return fmt.Sprintf("cannot print %q: odd number of backslashes before a quote, a newline or the end", err.ID)
+}
+
+// IsReadable reports whether id can be read back from DOT source. Quoted
+// strings read a backslash before a quote as the quote and a backslash before
+// a newline as nothing, and keep other backslashes with the character after
+// them, so an odd number of backslashes can be before neither a quote, a
+// newline nor the end of an ID.
+func IsReadable(id string) bool {
+	backslashes := 0
+	for _, char := range id {
+		switch char {
+		case '\\':
+			backslashes++
+			continue
+		case '"', '\n':
+			if backslashes%2 == 1 {
+				return false
+			}
+		}
+		backslashes = 0
+	}
+	return backslashes%2 == 0
+}
+
+// Print writes the graph as DOT source, one statement per line. Attribute
+// keys are sorted, so the output only depends on the content of the graph
+// and ParseFile(Print(graph)) yields an equal graph.
+//
+// Graphs with IDs that cannot be read from DOT source, see IsReadable, give
+// a PrintError.
+func Print(graph parser.Graph) Result[string] {
+	if graph.Name.IsSome() && !IsReadable(graph.Name.Unwrap()) {
+		return Err[string](&PrintError{ID: graph.Name.Unwrap()})
+	}
+	if err := check(graph.Statements); err != nil {
+		return Err[string](err)
+	}
+
+	var builder strings.Builder
+
+	if graph.IsStrict {
+		builder.WriteString("strict ")
+	}
+	arc := "--"
+	if graph.IsDirect {
+		builder.WriteString("digraph ")
+		arc = "->"
+	} else {
+		builder.WriteString("graph ")
+	}
+	if graph.Name.IsSome() {
+		builder.WriteString(ID(graph.Name.Unwrap()) + " ")
+	}
+
+	builder.WriteString("{\n")
+	printStatements(&builder, graph.Statements, arc, "\t")
+	builder.WriteString("}\n")
+	return Ok(builder.String())
+}
+
+// check returns a PrintError for the first ID of the statements that cannot
+// be read from DOT source
+func check(stmts []parser.Statement) error {
+	for _, stmt := range stmts {
+		var ids []string
+		var attributes []parser.AttributeMap
+		switch stmt := stmt.(type) {
+		case *parser.Node:
+			ids, attributes = nodeIDs(stmt.ID), stmt.Attributes
+		case *parser.Edge:
+			ids, attributes = append(nodeIDs(stmt.Lnode), nodeIDs(stmt.Rnode)...), stmt.Attributes
+		case *parser.AttributeStmt:
+			attributes = stmt.Attributes
+		case *parser.SingleAttribute:
+			ids = []string{stmt.Key, stmt.Value}
+		case *parser.Subgraph:
+			if stmt.Name.IsSome() {
+				ids = []string{stmt.Name.Unwrap()}
+			}
+		}
+		for _, attributeMap := range attributes {
+			for _, key := range attributeMap.Keys() {
+				ids = append(ids, key, attributeMap[key])
+			}
+		}
+		for _, id := range ids {
+			if !IsReadable(id) {
+				return &PrintError{ID: id}
+			}
+		}
+		if subgraph, ok := stmt.(*parser.Subgraph); ok {
+			if err := check(subgraph.Statements); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func nodeIDs(node parser.NodeID) []string {
+	if node.Port.IsSome() {
+		return []string{node.Name, node.Port.Unwrap()}
+	}
+	return []string{node.Name}
+}
+
+func printStatements(builder *strings.Builder, stmts []parser.Statement, arc string, indent string) {
+	for _, stmt := range stmts {
+		builder.WriteString(indent)
+		switch stmt := stmt.(type) {
+		case *parser.Node:
+			builder.WriteString(NodeID(stmt.ID) + attributeLists(stmt.Attributes))
+		case *parser.Edge:
+			builder.WriteString(NodeID(stmt.Lnode) + " " + arc + " " + NodeID(stmt.Rnode) + attributeLists(stmt.Attributes))
+		case *parser.AttributeStmt:
+			builder.WriteString(levelKeyword(stmt.Level) + attributeLists(stmt.Attributes))
+		case *parser.SingleAttribute:
+			builder.WriteString(ID(stmt.Key) + "=" + ID(stmt.Value))
+		case *parser.Subgraph:
+			builder.WriteString("subgraph ")
+			if stmt.Name.IsSome() {
+				builder.WriteString(ID(stmt.Name.Unwrap()) + " ")
+			}
+			builder.WriteString("{\n")
+			printStatements(builder, stmt.Statements, arc, indent+"\t")
+			builder.WriteString(indent + "}")
+		}
+		builder.WriteString("\n")
+	}
+}
+
+func attributeLists(attributes []parser.AttributeMap) string {
+	var out_string string
+	for _, attributeMap := range attributes {
+		out_string += " " + AttributeList(attributeMap)
+	}
+	return out_string
+}
+
+func levelKeyword(level parser.AttributeLevel) string {
+	switch level {
+	case parser.NODE_LEVEL:
+		return "node"
+	case parser.EDGE_LEVEL:
+		return "edge"
+	default:
+		return "graph"
+	}
+}
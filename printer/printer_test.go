@@ -0,0 +1,135 @@
+package printer
+
+import (
+	"dot-parser/builder"
+	"dot-parser/diff"
+	"dot-parser/internal/testutil"
+	"dot-parser/parser"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func reparse(t *testing.T, graph parser.Graph) parser.Graph {
+	printed := Print(graph)
+	if printed.IsErr() {
+		t.Fatalf("Expected graph to print, failed with %s", printed.UnwrapErr())
+	}
+	res := parser.ParseFile(strings.NewReader(printed.Unwrap()))
+	if res.IsErr() {
+		t.Fatalf("Expected printed graph to parse, failed with %s\n%s", res.UnwrapErr(), printed.Unwrap())
+	}
+	reparsed := res.Unwrap()
+	testutil.ClearPositions(reparsed.Statements)
+	return reparsed
+}
+
+func TestID(t *testing.T) {
+	ids := map[string]string{
+		"abc_1":      "abc_1",
+		"_x":         "_x",
+		"-.5":        "-.5",
+		"12.":        "12.",
+		"città":      "città",
+		"":           `""`,
+		"node":       `"node"`,
+		"Graph":      `"Graph"`,
+		"1a":         `"1a"`,
+		"a b":        `"a b"`,
+		"-":          `"-"`,
+		`say "hi"`:   `"say \"hi\""`,
+		`\N`:         `"\N"`,
+		"line\nfeed": "\"line\nfeed\"",
+	}
+
+	for id, expected := range ids {
+		if quoted := ID(id); quoted != expected {
+			t.Errorf("Expected %s for '%s', got %s", expected, id, quoted)
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	graph := builder.Digraph().Strict().Name("G").
+		Attr("rankdir", "LR").
+		NodeDefaults(parser.AttributeMap{"shape": "box", "color": "red"}).
+		EdgeID(builder.Port("a", "p"), builder.ID("b"), parser.AttributeMap{"label": "a b"}).
+		Subgraph("cluster_0", func(sub *builder.Builder) {
+			sub.Node("c")
+		}).
+		Build()
+
+	expected := `strict digraph G {
+	rankdir=LR
+	node [color=red, shape=box]
+	a:p -> b [label="a b"]
+	subgraph cluster_0 {
+		c
+	}
+}
+`
+	if printed := Print(graph).Unwrap(); printed != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, printed)
+	}
+}
+
+func TestPrintRoundTrip(t *testing.T) {
+	graph := builder.Graph().Name("my graph").
+		Attr("label", `say "hi" \N`).
+		Node("node", parser.AttributeMap{"label": "multi\nline"}, parser.AttributeMap{}).
+		Path([]string{"", "-1.5", "città"}, parser.AttributeMap{"weight": "2"}).
+		EdgeDefaults().
+		AnonymousSubgraph(func(sub *builder.Builder) {
+			sub.GraphDefaults(parser.AttributeMap{"rank": "same"}).Node("x y")
+		}).
+		Build()
+
+	reparsed := reparse(t, graph)
+	if !reflect.DeepEqual(graph, reparsed) {
+		t.Fatalf("Expected reparsed graph to match\nexpected: %#v\ngot:      %#v", graph, reparsed)
+	}
+
+	if !diff.Equal(graph, reparsed) {
+		t.Fatalf("Expected reparsed graph to be equal, got changes:\n%s", diff.Compare(graph, reparsed))
+	}
+}
+
+func TestPrintParsedRoundTrip(t *testing.T) {
+	res := parser.ParseFile(strings.NewReader(`digraph {
+		a -> b -> c [ color = "#ff0000" ]
+		"a \"quoted\" \\ id" [ label = "\l" ]
+		subgraph s { d:n -> e }
+	}`))
+	if res.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", res.UnwrapErr())
+	}
+	graph := res.Unwrap()
+	testutil.ClearPositions(graph.Statements)
+
+	if reparsed := reparse(t, graph); !reflect.DeepEqual(graph, reparsed) {
+		t.Fatalf("Expected reparsed graph to match\nexpected: %#v\ngot:      %#v", graph, reparsed)
+	}
+}
+
+func TestPrintBackslashes(t *testing.T) {
+	readable := []string{`a\\`, `a\\"b`, "a\\\\\nb", `\N`, `a\b`}
+	for _, value := range readable {
+		graph := builder.Graph().Node("a", parser.AttributeMap{"label": value}).Build()
+		if reparsed := reparse(t, graph); !reflect.DeepEqual(graph, reparsed) {
+			t.Errorf("Expected %q to round-trip, got %#v", value, reparsed)
+		}
+	}
+
+	unreadable := []string{`a\`, `a\\\`, `a\"b`, "a\\\nb"}
+	for _, value := range unreadable {
+		graph := builder.Graph().Subgraph("s", func(sub *builder.Builder) {
+			sub.Node("a", parser.AttributeMap{"label": value})
+		}).Build()
+		res := Print(graph)
+		if res.IsOk() {
+			t.Errorf("Expected %q not to print, got %s", value, res.Unwrap())
+		} else if err, ok := res.UnwrapErr().(*PrintError); !ok || err.ID != value {
+			t.Errorf("Expected a PrintError for %q, got %s", value, res.UnwrapErr())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package record
+
+import (
+	. "dot-parser/lexer"
+	"dot-parser/model"
+	"dot-parser/parser"
+	. "dot-parser/result"
+	"fmt"
+)
+
+var compassPoints = map[string]bool{
+	"n": true, "ne": true, "e": true, "se": true, "s": true,
+	"sw": true, "w": true, "nw": true, "c": true, "_": true,
+}
+
+type PortError struct {
+	Position Position
+	Node     string
+	Port     string
+}
+
+func (err *PortError) Error() string {
+	return fmt.Sprintf(
+		"Record error at line %d column %d: node \"%s\" has no port \"%s\"",
+		err.Position.Line(),
+		err.Position.Column(),
+		err.Node,
+		err.Port)
+}
+
+// IsRecordShape reports whether shape is one of the record based shapes
+func IsRecordShape(shape string) bool {
+	return shape == "record" || shape == "Mrecord"
+}
+
+// CheckPorts verifies that every port used by an edge of the graph exists on
+// its target node, when that node has a record shape. Compass points are
+// accepted on any node. Invalid record labels are reported as *LabelError,
+// missing ports as *PortError.
+func CheckPorts(graph parser.Graph) []error {
+	resolved := model.Resolve(graph)
+
+	var errors []error
+	labels := make(map[*model.Node]Result[Field])
+	for _, edge := range resolved.Edges {
+		for _, stmt := range edge.Statements {
+			for _, nodeID := range []parser.NodeID{stmt.Lnode, stmt.Rnode} {
+				if nodeID.Port.IsNone() || compassPoints[nodeID.Port.Unwrap()] {
+					continue
+				}
+
+				node := resolved.Node(nodeID.Name).Unwrap()
+				if !IsRecordShape(node.Attributes["shape"]) {
+					continue
+				}
+
+				label, parsed := labels[node]
+				if !parsed {
+					label = Parse(recordLabel(node.Attributes))
+					labels[node] = label
+					if label.IsErr() {
+						errors = append(errors, label.UnwrapErr())
+					}
+				}
+
+				if label.IsOk() && !hasPort(label.Unwrap(), nodeID.Port.Unwrap()) {
+					errors = append(errors, &PortError{
+						Position: stmt.Position,
+						Node:     nodeID.Name,
+						Port:     nodeID.Port.Unwrap(),
+					})
+				}
+			}
+		}
+	}
+	return errors
+}
+
+func recordLabel(attributes parser.AttributeMap) string {
+	if label, exists := attributes["label"]; exists {
+		return label
+	}
+	return "\\N"
+}
+
+func hasPort(label Field, port string) bool {
+	for _, name := range label.Ports() {
+		if name == port {
+			return true
+		}
+	}
+	return false
+}
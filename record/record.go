@@ -0,0 +1,169 @@
+package record
+
+import (
+	"dot-parser/option"
+	. "dot-parser/result"
+	"fmt"
+	"strings"
+)
+
+// Field is a field of a record label. A field either has text and an
+// optional port, or is a '{' '}' group of nested Fields.
+type Field struct {
+	Port   option.Option[string]
+	Text   string
+	Fields []Field
+}
+
+func (field Field) IsGroup() bool {
+	return field.Fields != nil
+}
+
+// Ports returns the port names defined by the field and all of its nested
+// fields, in label order
+func (field Field) Ports() []string {
+	var ports []string
+	if field.Port.IsSome() {
+		ports = append(ports, field.Port.Unwrap())
+	}
+	for _, child := range field.Fields {
+		ports = append(ports, child.Ports()...)
+	}
+	return ports
+}
+
+type LabelError struct {
+	Label   string
+	Offset  int
+	Message string
+}
+
+func (err *LabelError) Error() string {
+	return fmt.Sprintf("Record label error at offset %d of \"%s\": %s", err.Offset, err.Label, err.Message)
+}
+
+type labelParser struct {
+	label  []rune
+	offset int
+}
+
+// Parse reads a record label such as "<f0> left|{<f1> mid|<f2> right}".
+// The returned group field holds the top-level fields of the label.
+//
+// rlabel:  field ('|' field)*
+// field:   fieldId | '{' rlabel '}'
+// fieldId: ('<' text '>')? text
+func Parse(label string) Result[Field] {
+	parser := &labelParser{label: []rune(label)}
+
+	fields := parser.parseFields()
+	return FlatMap(fields, func(fields []Field) Result[Field] {
+		if !parser.isAtEnd() {
+			return parser.makeError("unexpected '" + string(parser.peek()) + "'")
+		}
+		return Ok(Field{Port: option.None[string](), Fields: fields})
+	})
+}
+
+func (parser *labelParser) parseFields() Result[[]Field] {
+	var fields []Field
+	for {
+		var field Result[Field]
+		if parser.peek() == '{' {
+			field = parser.parseGroup()
+		} else {
+			field = parser.parseFieldID()
+		}
+
+		if field.IsErr() {
+			return Err[[]Field](field.UnwrapErr())
+		}
+		fields = append(fields, field.Unwrap())
+
+		if parser.peek() != '|' {
+			return Ok(fields)
+		}
+		parser.offset += 1
+	}
+}
+
+func (parser *labelParser) parseGroup() Result[Field] {
+	parser.offset += 1
+	fields := parser.parseFields()
+	return FlatMap(fields, func(fields []Field) Result[Field] {
+		if parser.peek() != '}' {
+			return parser.makeError("expected '}'")
+		}
+		parser.offset += 1
+		return Ok(Field{Port: option.None[string](), Fields: fields})
+	})
+}
+
+func (parser *labelParser) parseFieldID() Result[Field] {
+	field := Field{Port: option.None[string]()}
+
+	text := parser.parseText("<{")
+	if parser.peek() == '<' {
+		if strings.TrimSpace(text) != "" {
+			return parser.makeError("port must precede the field text")
+		}
+		parser.offset += 1
+		port := parser.parseText("<>{}|")
+		if parser.peek() != '>' {
+			return parser.makeError("expected '>'")
+		}
+		parser.offset += 1
+		field.Port = option.Some(strings.TrimSpace(port))
+		text = parser.parseText("<>{")
+	}
+
+	if next := parser.peek(); next == '<' || next == '>' || next == '{' {
+		return parser.makeError("unexpected '" + string(next) + "'")
+	}
+	field.Text = strings.TrimSpace(text)
+	return Ok(field)
+}
+
+// parseText reads until an unescaped '|' or '}' or one of the stop runes.
+// The record metacharacters and space can be escaped with '\'; any other
+// escape sequence is kept as it is.
+func (parser *labelParser) parseText(stop string) string {
+	var builder strings.Builder
+	for !parser.isAtEnd() {
+		char := parser.peek()
+		if char == '|' || char == '}' || strings.ContainsRune(stop, char) {
+			break
+		}
+
+		parser.offset += 1
+		if char == '\\' && !parser.isAtEnd() {
+			escaped := parser.peek()
+			if strings.ContainsRune("{}|<> ", escaped) {
+				parser.offset += 1
+				builder.WriteRune(escaped)
+				continue
+			}
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+func (parser *labelParser) isAtEnd() bool {
+	return parser.offset >= len(parser.label)
+}
+
+func (parser *labelParser) peek() rune {
+	if parser.isAtEnd() {
+		return 0
+	}
+	return parser.label[parser.offset]
+}
+
+func (parser *labelParser) makeError(message string) Result[Field] {
+	return Err[Field](&LabelError{
+		Label:   string(parser.label),
+		Offset:  parser.offset,
+		Message: message,
+	})
+}
@@ -0,0 +1,74 @@
+package record
+
+import (
+	"dot-parser/parser"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRecordLabel(t *testing.T) {
+	res := Parse("<f0> left|{<f1> mid\\|dle|<f2> right}")
+	if res.IsErr() {
+		t.Fatalf("Expected record label, failed with %s", res.UnwrapErr())
+	}
+
+	root := res.Unwrap()
+	if len(root.Fields) != 2 {
+		t.Fatalf("Expected 2 top-level fields, got %v", root.Fields)
+	}
+
+	if left := root.Fields[0]; left.IsGroup() || left.Port.OrElse("") != "f0" || left.Text != "left" {
+		t.Fatalf("Expected field 'left' with port 'f0', got %v", left)
+	}
+
+	group := root.Fields[1]
+	if !group.IsGroup() || len(group.Fields) != 2 {
+		t.Fatalf("Expected group with 2 fields, got %v", group)
+	}
+
+	if mid := group.Fields[0]; mid.Port.OrElse("") != "f1" || mid.Text != "mid|dle" {
+		t.Fatalf("Expected field 'mid|dle' with port 'f1', got %v", mid)
+	}
+
+	if ports := root.Ports(); strings.Join(ports, ",") != "f0,f1,f2" {
+		t.Fatalf("Expected ports f0, f1, f2, got %v", ports)
+	}
+}
+
+func TestParseInvalidRecordLabel(t *testing.T) {
+	for _, label := range []string{"{a|b", "a}b", "<f0 a", "a <f0> b"} {
+		res := Parse(label)
+		var labelErr *LabelError
+		if res.IsOk() || !errors.As(res.UnwrapErr(), &labelErr) {
+			t.Errorf("Expected label error for '%s', got %v", label, res)
+		}
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	res := parser.ParseFile(strings.NewReader(`digraph {
+		node [ shape = record ]
+		a [ label = "<f0> left|<f1> right" ]
+		b
+		a:f1 -> b:n
+		a:f2 -> c:p
+	}`))
+	if res.IsErr() {
+		t.Fatalf("Expected Graph, failed with %s", res.UnwrapErr())
+	}
+
+	errs := CheckPorts(res.Unwrap())
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 port errors, got %v", errs)
+	}
+
+	var portErr *PortError
+	if !errors.As(errs[0], &portErr) || portErr.Node != "a" || portErr.Port != "f2" || portErr.Position.Line() != 6 {
+		t.Fatalf("Expected missing port 'f2' on node 'a' at line 6, got %s", errs[0])
+	}
+
+	if !errors.As(errs[1], &portErr) || portErr.Node != "c" || portErr.Port != "p" {
+		t.Fatalf("Expected missing port 'p' on node 'c', got %s", errs[1])
+	}
+}